@@ -73,6 +73,7 @@ func (b *BlockStmt) String() string {
 
 type StopStmt struct {
 	Token *token.Token
+	Label *Ident // optional
 
 	stmt
 }
@@ -81,6 +82,7 @@ func (*StopStmt) String() string { return "stop" }
 
 type NextStmt struct {
 	Token *token.Token
+	Label *Ident // optional
 
 	stmt
 }
@@ -100,6 +102,32 @@ type ObjectDecl struct {
 
 func (*ObjectDecl) String() string { return "ObjectDecl" }
 
+type RecordDecl struct {
+	Token        *token.Token
+	Name         *Ident
+	FieldList    []*VarDecl
+	FunctionList []*FunDecl // optional body
+
+	stmt
+}
+
+func (*RecordDecl) String() string { return "RecordDecl" }
+
+type EnumDecl struct {
+	Token   *token.Token
+	Name    *Ident
+	Members []*EnumMember
+
+	stmt
+}
+
+func (*EnumDecl) String() string { return "EnumDecl" }
+
+type EnumMember struct {
+	Name  *Ident
+	Value Expr // optional
+}
+
 type AssignStmt struct {
 	Token *token.Token
 	Left  []Expr
@@ -163,9 +191,11 @@ type ParameterizedType struct {
 func (t *ParameterizedType) String() string { return "ast.Type" }
 
 type VarDecl struct {
-	Name  *Ident
-	Type  Type
-	Value Expr
+	Name    *Ident
+	Type    Type
+	Value   Expr
+	Rest    bool // collects the remaining arguments
+	Keyword bool // passed by name
 
 	stmt
 }
@@ -229,7 +259,9 @@ type IfStmt struct {
 func (*IfStmt) String() string { return "IfStmt" }
 
 type ForStmt struct {
+	Label    *Ident // optional
 	Element  *Ident
+	Value    *Ident // optional
 	Iterable Expr
 	Body     *BlockStmt
 
@@ -239,22 +271,32 @@ type ForStmt struct {
 func (*ForStmt) String() string { return "ForStmt" }
 
 type WhileStmt struct {
-	Cond Expr
-	Body *BlockStmt
+	Label *Ident // optional
+	Cond  Expr
+	Body  *BlockStmt
 
 	stmt
 }
 
 func (*WhileStmt) String() string { return "WhileStmt" }
 
+/*
+Each case holds one or more alternative patterns, a pattern is
+an expression matched against the switch key: identifiers bind
+the key (or part of it) to a local, _ matches anything, constants
+match by type and array or hash literals match by shape
+*/
 type CaseClause struct {
-	Value Expr
-	Body  *BlockStmt
+	Token    *token.Token
+	Patterns []Expr
+	Guard    Expr // optional
+	Body     *BlockStmt
 }
 
 func (*CaseClause) String() string { return "CaseClause" }
 
 type SwitchStmt struct {
+	Token   *token.Token
 	Key     Expr
 	Cases   []*CaseClause
 	Default *CaseClause
@@ -275,8 +317,8 @@ func (e *ExprStmt) String() string {
 }
 
 type ReturnStmt struct {
-	Token *token.Token
-	Value Expr
+	Token   *token.Token
+	Results []Expr
 
 	stmt
 }
@@ -284,7 +326,13 @@ type ReturnStmt struct {
 func (r *ReturnStmt) String() string {
 	var buf strings.Builder
 	buf.WriteString("return ")
-	buf.WriteString(r.Value.String())
+	for i, result := range r.Results {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(result.String())
+	}
 
 	return buf.String()
 }
@@ -319,6 +367,32 @@ func (i *Ident) IsConstant() bool {
 
 func (i *Ident) String() string { return i.Value }
 
+/*
+A module-level variable, shared by every function
+and by the files loaded with use
+*/
+type Global struct {
+	Token *token.Token
+	Value string
+
+	expr
+}
+
+func (g *Global) String() string { return "$" + g.Value }
+
+/*
+Matches the value of a local in a case pattern, where
+a bare name would bind a new local instead
+*/
+type PinExpr struct {
+	Caret *token.Token
+	Name  *Ident
+
+	expr
+}
+
+func (p *PinExpr) String() string { return "^" + p.Name.Value }
+
 type UnaryExpr struct {
 	Operator *token.Token
 	Expr     Expr
@@ -375,6 +449,7 @@ type ArrayLit struct {
 
 func (*ArrayLit) String() string { return "ArrayLit" }
 
+// Splat entries hold the SplatExpr in Key and no Value
 type HashEntry struct {
 	Key   Expr
 	Colon *token.Token
@@ -406,6 +481,116 @@ type IndexExpr struct {
 
 func (*IndexExpr) String() string { return "IndexExpr" }
 
+// The for clause of a comprehension
+type CompClause struct {
+	Token    *token.Token
+	Element  *Ident
+	Value    *Ident // optional
+	Iterable Expr
+	Cond     Expr // optional
+}
+
+func (*CompClause) String() string { return "CompClause" }
+
+// An array built by a loop
+type ArrayComp struct {
+	LeftBracket  *token.Token
+	Element      Expr
+	Clause       *CompClause
+	RightBracket *token.Token
+
+	expr
+}
+
+func (*ArrayComp) String() string { return "ArrayComp" }
+
+// A hash built by a loop
+type HashComp struct {
+	LeftBrace  *token.Token
+	Entry      *HashEntry
+	Clause     *CompClause
+	RightBrace *token.Token
+
+	expr
+}
+
+func (*HashComp) String() string { return "HashComp" }
+
+// An if producing the value of the taken branch
+type IfExpr struct {
+	Stmt *IfStmt
+
+	expr
+}
+
+func (*IfExpr) String() string { return "IfExpr" }
+
+// A switch producing the value of the matching case
+type SwitchExpr struct {
+	Stmt *SwitchStmt
+
+	expr
+}
+
+func (*SwitchExpr) String() string { return "SwitchExpr" }
+
+// The conditional operator cond ? a : b
+type CondExpr struct {
+	Cond     Expr
+	Question *token.Token
+	Then     Expr
+	Else     Expr
+
+	expr
+}
+
+func (c *CondExpr) String() string {
+	var buf strings.Builder
+	buf.WriteByte('(')
+	buf.WriteString(c.Cond.String())
+	buf.WriteString(" ? ")
+	buf.WriteString(c.Then.String())
+	buf.WriteString(" : ")
+	buf.WriteString(c.Else.String())
+	buf.WriteByte(')')
+
+	return buf.String()
+}
+
+// Collects the remaining elements when destructuring
+type RestExpr struct {
+	Expr     Expr
+	Ellipsis *token.Token
+
+	expr
+}
+
+func (r *RestExpr) String() string { return r.Expr.String() + "..." }
+
+/*
+Spreads an array into arguments or elements,
+or a hash into the entries of another
+*/
+type SplatExpr struct {
+	Star *token.Token
+	Expr Expr
+
+	expr
+}
+
+func (s *SplatExpr) String() string { return s.Star.String() + s.Expr.String() }
+
+// An argument passed by name
+type KeywordArg struct {
+	Name  *Ident
+	Colon *token.Token
+	Value Expr
+
+	expr
+}
+
+func (k *KeywordArg) String() string { return k.Name.Value + ": " + k.Value.String() }
+
 type CallExpr struct {
 	Function  Expr
 	Arguments []Expr
@@ -454,6 +639,7 @@ type MemberExpr struct {
 	Base Expr
 	Dot  *token.Token
 	Name *Ident
+	Safe bool // base?.name yields none when base is none
 
 	expr
 }
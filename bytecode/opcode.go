@@ -4,33 +4,46 @@ package bytecode
 type Opcode byte
 
 const (
-	Nop            Opcode = iota // NOP
-	Pop                          // POP
-	Push                         // PUSH
-	Throw                        // THROW
-	Return                       // RETURN
-	PushNone                     // PUSH_NONE
-	SetField                     // SET_FIELD
-	GetField                     // GET_FIELD
-	PushThis                     // PUSH_THIS
-	SetLocal                     // SET_LOCAL
-	GetLocal                     // GET_LOCAL
-	MatchType                    // MATCH_TYPE
-	BuildArray                   // BUILD_ARRAY
-	BuildHash                    // BUILD_HASH
-	CallMethod                   // CALL_METHOD
-	CallSuper                    // CALL_SUPER
-	SetConstant                  // SET_CONSTANT
-	GetConstant                  // GET_CONSTANT
-	DefineObject                 // DEFINE_OBJECT
-	DefineField                  // DEFINE_FIELD
-	DefineFunction               // DEFINE_FUNCTION
-	Jump                         // JUMP
-	JumpIfFalse                  // JUMP_IF_FALSE
-	JumpIfTrue                   // JUMP_IF_TRUE
-	Iterate                      // ITERATE
-	NewIterator                  // NEWITERATOR
-	LoadFile                     // LOAD_FILE
+	Nop                Opcode = iota // NOP
+	Pop                              // POP
+	Dup                              // DUP
+	Push                             // PUSH
+	Throw                            // THROW
+	Return                           // RETURN
+	PushNone                         // PUSH_NONE
+	SetField                         // SET_FIELD
+	GetField                         // GET_FIELD
+	PushThis                         // PUSH_THIS
+	SetLocal                         // SET_LOCAL
+	GetLocal                         // GET_LOCAL
+	MatchType                        // MATCH_TYPE
+	BuildArray                       // BUILD_ARRAY
+	BuildHash                        // BUILD_HASH
+	BuildRange                       // BUILD_RANGE
+	UnpackArray                      // UNPACK_ARRAY
+	UnpackRest                       // UNPACK_REST
+	In                               // IN
+	CallMethod                       // CALL_METHOD
+	CallSuper                        // CALL_SUPER
+	SetConstant                      // SET_CONSTANT
+	GetConstant                      // GET_CONSTANT
+	SetGlobal                        // SET_GLOBAL
+	GetGlobal                        // GET_GLOBAL
+	DefineObject                     // DEFINE_OBJECT
+	DefineEnum                       // DEFINE_ENUM
+	DefineField                      // DEFINE_FIELD
+	DefineFunction                   // DEFINE_FUNCTION
+	Jump                             // JUMP
+	JumpIfFalse                      // JUMP_IF_FALSE
+	JumpIfTrue                       // JUMP_IF_TRUE
+	JumpIfFalseOrPop                 // JUMP_IF_FALSE_OR_POP
+	JumpIfTrueOrPop                  // JUMP_IF_TRUE_OR_POP
+	JumpIfNotNoneOrPop               // JUMP_IF_NOT_NONE_OR_POP
+	JumpIfNone                       // JUMP_IF_NONE
+	JumpTable                        // JUMP_TABLE
+	Iterate                          // ITERATE
+	NewIterator                      // NEWITERATOR
+	LoadFile                         // LOAD_FILE
 
 	/*
 		┌──────────────────────── INTERNAL OPCODES ────────────────────────────┐
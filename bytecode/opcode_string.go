@@ -10,37 +10,50 @@ func _() {
 	var x [1]struct{}
 	_ = x[Nop-0]
 	_ = x[Pop-1]
-	_ = x[Push-2]
-	_ = x[Throw-3]
-	_ = x[Return-4]
-	_ = x[PushNone-5]
-	_ = x[SetField-6]
-	_ = x[GetField-7]
-	_ = x[PushThis-8]
-	_ = x[SetLocal-9]
-	_ = x[GetLocal-10]
-	_ = x[MatchType-11]
-	_ = x[BuildArray-12]
-	_ = x[BuildHash-13]
-	_ = x[CallMethod-14]
-	_ = x[CallSuper-15]
-	_ = x[SetConstant-16]
-	_ = x[GetConstant-17]
-	_ = x[DefineObject-18]
-	_ = x[DefineField-19]
-	_ = x[DefineFunction-20]
-	_ = x[Jump-21]
-	_ = x[JumpIfFalse-22]
-	_ = x[JumpIfTrue-23]
-	_ = x[Iterate-24]
-	_ = x[NewIterator-25]
-	_ = x[LoadFile-26]
-	_ = x[WithCatch-27]
+	_ = x[Dup-2]
+	_ = x[Push-3]
+	_ = x[Throw-4]
+	_ = x[Return-5]
+	_ = x[PushNone-6]
+	_ = x[SetField-7]
+	_ = x[GetField-8]
+	_ = x[PushThis-9]
+	_ = x[SetLocal-10]
+	_ = x[GetLocal-11]
+	_ = x[MatchType-12]
+	_ = x[BuildArray-13]
+	_ = x[BuildHash-14]
+	_ = x[BuildRange-15]
+	_ = x[UnpackArray-16]
+	_ = x[UnpackRest-17]
+	_ = x[In-18]
+	_ = x[CallMethod-19]
+	_ = x[CallSuper-20]
+	_ = x[SetConstant-21]
+	_ = x[GetConstant-22]
+	_ = x[SetGlobal-23]
+	_ = x[GetGlobal-24]
+	_ = x[DefineObject-25]
+	_ = x[DefineEnum-26]
+	_ = x[DefineField-27]
+	_ = x[DefineFunction-28]
+	_ = x[Jump-29]
+	_ = x[JumpIfFalse-30]
+	_ = x[JumpIfTrue-31]
+	_ = x[JumpIfFalseOrPop-32]
+	_ = x[JumpIfTrueOrPop-33]
+	_ = x[JumpIfNotNoneOrPop-34]
+	_ = x[JumpIfNone-35]
+	_ = x[JumpTable-36]
+	_ = x[Iterate-37]
+	_ = x[NewIterator-38]
+	_ = x[LoadFile-39]
+	_ = x[WithCatch-40]
 }
 
-const _Opcode_name = "NOPPOPPUSHTHROWRETURNPUSH_NONESET_FIELDGET_FIELDPUSH_THISSET_LOCALGET_LOCALMATCH_TYPEBUILD_ARRAYBUILD_HASHCALL_METHODCALL_SUPERSET_CONSTANTGET_CONSTANTDEFINE_OBJECTDEFINE_FIELDDEFINE_FUNCTIONJUMPJUMP_IF_FALSEJUMP_IF_TRUEITERATENEWITERATORLOAD_FILEWITH_CATCH"
+const _Opcode_name = "NOPPOPDUPPUSHTHROWRETURNPUSH_NONESET_FIELDGET_FIELDPUSH_THISSET_LOCALGET_LOCALMATCH_TYPEBUILD_ARRAYBUILD_HASHBUILD_RANGEUNPACK_ARRAYUNPACK_RESTINCALL_METHODCALL_SUPERSET_CONSTANTGET_CONSTANTSET_GLOBALGET_GLOBALDEFINE_OBJECTDEFINE_ENUMDEFINE_FIELDDEFINE_FUNCTIONJUMPJUMP_IF_FALSEJUMP_IF_TRUEJUMP_IF_FALSE_OR_POPJUMP_IF_TRUE_OR_POPJUMP_IF_NOT_NONE_OR_POPJUMP_IF_NONEJUMP_TABLEITERATENEWITERATORLOAD_FILEWITH_CATCH"
 
-var _Opcode_index = [...]uint16{0, 3, 6, 10, 15, 21, 30, 39, 48, 57, 66, 75, 85, 96, 106, 117, 127, 139, 151, 164, 176, 191, 195, 208, 220, 227, 238, 247, 257}
+var _Opcode_index = [...]uint16{0, 3, 6, 9, 13, 18, 24, 33, 42, 51, 60, 69, 78, 88, 99, 109, 120, 132, 143, 145, 156, 166, 178, 190, 200, 210, 223, 234, 246, 261, 265, 278, 290, 310, 329, 352, 364, 374, 381, 392, 401, 411}
 
 func (i Opcode) String() string {
 	if i >= Opcode(len(_Opcode_index)-1) {
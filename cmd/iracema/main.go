@@ -18,6 +18,10 @@ func report(mesg string) {
 	fmt.Fprintf(os.Stderr, "\x1b[0;31m%s\x1b[0;0m\n", mesg)
 }
 
+func warn(mesg string) {
+	fmt.Fprintf(os.Stderr, "\x1b[0;33m%s\x1b[0;0m\n", mesg)
+}
+
 func parseFile(file string) *ast.File {
 	f, err := os.Open(file)
 	if err != nil {
@@ -50,6 +54,10 @@ func runFile(file string) {
 		os.Exit(60)
 	}
 
+	for _, warning := range c.Warnings() {
+		warn(warning)
+	}
+
 	interp := &interpreter.Interpreter{}
 	ret, err := interp.Exec(meth)
 	if err != nil {
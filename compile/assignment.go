@@ -0,0 +1,157 @@
+package compile
+
+import (
+	"fmt"
+	"iracema/bytecode"
+)
+
+/*
+* Definite assignment: a local read in the source has to be assigned
+* on every path from the entry of the fragment to the read.
+*
+* The locals assigned when a basicblock starts are the ones assigned
+* at the end of all of its predecessors, the analysis walks the CFG
+* narrowing them down until nothing changes. Locals are tracked by
+* identity, not by slot, as blocks reuse the slots of ended ones.
+*
+* if c { x = 1 }
+* puts(x)
+*
+*   ┌──────────────┐
+*   │ {}           │
+*   └──┬────────┬──┘
+*      │   ┌────▼─────────┐
+*      │   │ {} x = 1 {x} │
+*      │   └────┬─────────┘
+*   ┌──▼────────▼──┐
+*   │ {} ∩ {x}     │  puts(x) is reported
+*   └──────────────┘
+****/
+
+type assigned []uint64
+
+func newAssigned(size int) assigned {
+	return make(assigned, (size+63)/64)
+}
+
+func (a assigned) has(id int) bool { return a[id/64]&(1<<(id%64)) != 0 }
+func (a assigned) add(id int)      { a[id/64] |= 1 << (id % 64) }
+
+func (a assigned) copy() assigned {
+	c := make(assigned, len(a))
+	copy(c, a)
+	return c
+}
+
+/*
+Keeps in a only the locals also in b, reporting whether it changed
+*/
+func (a assigned) intersect(b assigned) bool {
+	changed := false
+	for i := range a {
+		if narrowed := a[i] & b[i]; narrowed != a[i] {
+			a[i] = narrowed
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+/*
+Blocks ins branches to. WITH_CATCH is the only one not ending
+its block, the handler sees the locals assigned before it
+*/
+func branches(ins *instr) []*basicblock {
+	if ins.table != nil {
+		return append(ins.table.targets[:len(ins.table.targets):len(ins.table.targets)], ins.table.miss)
+	}
+
+	if ins.hasTarget() {
+		return []*basicblock{ins.target}
+	}
+
+	return nil
+}
+
+/*
+Fails on the first read in block of a local not in state
+*/
+func checkReads(block *basicblock, state assigned) error {
+	for _, ins := range block.instrs {
+		if ins.local == nil {
+			continue
+		}
+
+		if ins.opcode == bytecode.SetLocal {
+			state.add(ins.local.id)
+			continue
+		}
+
+		if ins.read != nil && !state.has(ins.local.id) {
+			tok := ins.read
+			return fmt.Errorf("[Lin: %d Col: %d] '%s' is read before being assigned", tok.Line(), tok.Column(), ins.local.name)
+		}
+	}
+
+	return nil
+}
+
+func (c *compiler) checkAssignments() error {
+	entry := newAssigned(len(c.declared))
+	for _, l := range c.declared {
+		if l.param {
+			entry.add(l.id)
+		}
+	}
+
+	in := map[*basicblock]assigned{c.entrypoint: entry}
+	work := new(stack)
+	work.Push(c.entrypoint)
+
+	flow := func(succ *basicblock, out assigned) {
+		state, seen := in[succ]
+		if !seen {
+			in[succ] = out.copy()
+			work.Push(succ)
+			return
+		}
+
+		if state.intersect(out) {
+			work.Push(succ)
+		}
+	}
+
+	for !work.Empty() {
+		block := work.Pop()
+
+		out := in[block].copy()
+		for _, ins := range block.instrs {
+			for _, succ := range branches(ins) {
+				flow(succ, out)
+			}
+
+			if ins.local != nil && ins.opcode == bytecode.SetLocal {
+				out.add(ins.local.id)
+			}
+		}
+
+		if block.next != nil && block.hasFallthrough() {
+			flow(block.next, out)
+		}
+	}
+
+	// in block order, the first read in the source is the one reported
+	for block := c.entrypoint; block != nil; block = block.next {
+		state, reachable := in[block]
+		if !reachable {
+			continue
+		}
+
+		if err := checkReads(block, state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -47,12 +47,26 @@ func (b *basicblock) isDone() bool {
 
 	index := len(b.instrs) - 1
 	ins := b.instrs[index]
-	return ins.opcode == bytecode.Jump ||
-		ins.opcode == bytecode.JumpIfTrue ||
-		ins.opcode == bytecode.JumpIfFalse ||
+	return isJump(ins.opcode) ||
+		ins.opcode == bytecode.JumpTable ||
 		ins.opcode == bytecode.Return
 }
 
+/*
+Reports whether op branches to the instr in its operand
+*/
+func isJump(op bytecode.Opcode) bool {
+	switch op {
+	case
+		bytecode.Jump, bytecode.JumpIfTrue, bytecode.JumpIfFalse,
+		bytecode.JumpIfTrueOrPop, bytecode.JumpIfFalseOrPop,
+		bytecode.JumpIfNotNoneOrPop, bytecode.JumpIfNone:
+		return true
+	}
+
+	return false
+}
+
 func (b *basicblock) hasFallthrough() bool {
 	// no instrs we consider a fallthrough
 	if len(b.instrs) == 0 {
@@ -7,7 +7,9 @@ import (
 	"iracema/bytecode"
 	"iracema/lang"
 	"iracema/token"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 var unaryOps = map[token.Type]string{
@@ -27,8 +29,14 @@ var binaryOps = map[token.Type]string{
 	token.LessEqual:  "<=",
 	token.Great:      ">",
 	token.GreatEqual: ">=",
-	token.Or:         "or",
-	token.And:        "and",
+	token.Is:         "is_a?",
+}
+
+// operators deciding whether their right operand is evaluated at all
+var shortCircuitOps = map[token.Type]bytecode.Opcode{
+	token.And:              bytecode.JumpIfFalseOrPop,
+	token.Or:               bytecode.JumpIfTrueOrPop,
+	token.QuestionQuestion: bytecode.JumpIfNotNoneOrPop,
 }
 
 const (
@@ -42,16 +50,23 @@ const (
 
 type controlflow struct {
 	loop  int
+	label string
 	start *basicblock
 	exit  *basicblock
 
+	// valued branches open in the loop body, their operands are still on the stack
+	valued int
+
 	next *controlflow
 }
 
 type local struct {
-	name        string
-	index       byte
-	initialized bool
+	name  string
+	index byte
+	id    int // position in declared, slots are shared between blocks
+	depth int // block the local is visible in, 0 for the whole function
+	param bool
+	byVar bool // declared with var, it cannot be declared again in its block
 }
 
 func (l *local) String() string {
@@ -62,20 +77,62 @@ type instr struct {
 	opcode  bytecode.Opcode
 	operand byte
 	target  *basicblock
+	table   *jumptable
+
+	// the local stored or read, only reads from the source have a token
+	local *local
+	read  *token.Token
 }
 
 func (i *instr) hasTarget() bool {
 	return i.target != nil
 }
 
+/*
+Targets of a JUMP_TABLE instr, they are written into the
+lang.JumpTable in the constants once the offsets are known
+*/
+type jumptable struct {
+	keys    []lang.IrObject
+	targets []*basicblock
+	miss    *basicblock
+	object  *lang.JumpTable
+}
+
+const maxJumpOffset = 255
+
+func (t *jumptable) fits() bool {
+	for _, target := range t.targets {
+		if target.offset > maxJumpOffset {
+			return false
+		}
+	}
+
+	return t.miss.offset <= maxJumpOffset
+}
+
+func (t *jumptable) patch() {
+	for i, key := range t.keys {
+		t.object.Add(key, byte(t.targets[i].offset))
+	}
+
+	t.object.Default = byte(t.miss.offset)
+	t.object.Compact()
+}
+
 type fragment struct {
 	name         string
 	scope        int
 	argc         byte
 	optArgc      byte
+	rest         bool
+	keywords     []lang.KeywordParam
 	consts       []lang.IrObject
 	paramIndices []byte
-	locals       []*local
+	locals       []*local // visible from the current block
+	declared     []*local // every local, slots included
+	slots        []bool   // slots in use, blocks release theirs when they end
+	depth        int
 	catchOffset  int
 
 	control    *controlflow
@@ -87,6 +144,10 @@ type fragment struct {
 type compiler struct {
 	*fragment
 	fragments []*fragment
+	warnings  []string
+
+	// members of the enums declared, switches over them must cover them all
+	enums map[string][]string
 }
 
 func New() *compiler {
@@ -105,6 +166,7 @@ func (c *compiler) init() {
 		entrypoint: blk,
 	}
 
+	c.enums = make(map[string][]string)
 	c.fragments = append(c.fragments, c.fragment)
 }
 
@@ -117,13 +179,32 @@ func (c *compiler) Compile(file *ast.File) (*lang.Method, error) {
 		return nil, err
 	}
 
-	return c.assemble(), nil
+	return c.assemble()
+}
+
+/*
+Returns the warnings found while compiling, they
+do not prevent the code from being compiled
+*/
+func (c *compiler) Warnings() []string {
+	return c.warnings
+}
+
+func (c *compiler) warn(tok *token.Token, mesg string) {
+	warning := fmt.Sprintf("[Lin: %d Col: %d] warning: %s", tok.Line(), tok.Column(), mesg)
+	c.warnings = append(c.warnings, warning)
 }
 
-func (c *compiler) assemble() *lang.Method {
+func (c *compiler) assemble() (*lang.Method, error) {
+	if err := c.checkAssignments(); err != nil {
+		return nil, err
+	}
+
 	var bytecode []uint16
 	markReachable(c.entrypoint)
-	c.patchJumps()
+	if err := c.patchJumps(); err != nil {
+		return nil, err
+	}
 
 	for block := c.entrypoint; block != nil; block = block.next {
 		for _, instr := range block.instrs {
@@ -136,11 +217,13 @@ func (c *compiler) assemble() *lang.Method {
 		c.name,
 		c.argc,
 		c.optArgc,
+		c.rest,
+		c.keywords,
 		bytecode,
-		byte(len(c.locals)),
+		byte(len(c.slots)),
 		c.consts,
 		c.catchOffset,
-	)
+	), nil
 }
 
 type stack []*basicblock
@@ -179,15 +262,29 @@ func skipEmptyBlocks(entrypoint *basicblock) {
 		}
 
 		for _, ins := range block.instrs {
-			if ins.opcode == bytecode.Jump || ins.opcode == bytecode.JumpIfTrue || ins.opcode == bytecode.JumpIfFalse {
-				for len(ins.target.instrs) == 0 {
-					ins.target = ins.target.next
+			if isJump(ins.opcode) {
+				ins.target = skipEmpty(ins.target)
+			}
+
+			if ins.table != nil {
+				for i, target := range ins.table.targets {
+					ins.table.targets[i] = skipEmpty(target)
 				}
+
+				ins.table.miss = skipEmpty(ins.table.miss)
 			}
 		}
 	}
 }
 
+func skipEmpty(block *basicblock) *basicblock {
+	for len(block.instrs) == 0 {
+		block = block.next
+	}
+
+	return block
+}
+
 func markReachable(entrypoint *basicblock) {
 	if entrypoint == nil {
 		return
@@ -220,6 +317,15 @@ func markReachable(entrypoint *basicblock) {
 					s.Push(target)
 				}
 			}
+
+			if ins.table != nil {
+				for _, target := range append(ins.table.targets, ins.table.miss) {
+					if !target.visited {
+						target.reachable = true
+						s.Push(target)
+					}
+				}
+			}
 		}
 	}
 
@@ -232,7 +338,11 @@ func markReachable(entrypoint *basicblock) {
 	}
 }
 
-func (c *compiler) patchJumps() {
+/*
+Jump operands are a single byte, a function fails to
+compile when it jumps past its first 256 instrs
+*/
+func (c *compiler) patchJumps() error {
 	var start int
 	for block := c.entrypoint; block != nil; block = block.next {
 		block.offset = start
@@ -248,10 +358,28 @@ func (c *compiler) patchJumps() {
 					continue
 				}
 
+				if ins.target.offset > maxJumpOffset {
+					return c.tooLong()
+				}
+
 				ins.operand = byte(ins.target.offset)
 			}
+
+			if ins.table != nil {
+				if !ins.table.fits() {
+					return c.tooLong()
+				}
+
+				ins.table.patch()
+			}
 		}
 	}
+
+	return nil
+}
+
+func (c *compiler) tooLong() error {
+	return fmt.Errorf("%s is too long to compile, jumps cannot reach past instr %d", c.name, maxJumpOffset)
 }
 
 func (c *compiler) compileStmt(stmt ast.Stmt) error {
@@ -275,51 +403,29 @@ func (c *compiler) compileStmt(stmt ast.Stmt) error {
 	case *ast.ExprStmt:
 		return c.compileExpr(node.Expr, false)
 
+	case *ast.ConstDecl:
+		return c.compileConstDecl(node)
+
 	case *ast.VarDecl:
-		local := c.defineLocal(node.Name, false)
+		// the value is compiled first, so var n = n * 2 reads the shadowed n
 		if node.Value != nil {
 			if err := c.compileExpr(node.Value, true); err != nil {
 				return err
 			}
-			local.initialized = true
-			c.add(bytecode.SetLocal, local.index)
 		}
 
-	case *ast.AssignStmt:
-		for i, value := range node.Right {
-			switch lhs := node.Left[i].(type) {
-			case *ast.Ident:
-				local := c.defineLocal(lhs, false)
-				if err := c.compileExpr(value, true); err != nil {
-					return err
-				}
-				local.initialized = true
-				c.add(bytecode.SetLocal, local.index)
-
-			case *ast.IndexExpr:
-				if err := c.compileExpr(lhs.Expr, true); err != nil {
-					return err
-				}
-
-				if err := c.compileExpr(lhs.Index, true); err != nil {
-					return err
-				}
-
-				if err := c.compileExpr(value, true); err != nil {
-					return err
-				}
+		local, err := c.declareLocal(node.Name)
+		if err != nil {
+			return err
+		}
 
-				ci := lang.NewCallInfo("insert", 2)
-				c.add(bytecode.CallMethod, c.addConstant(ci))
-				c.add(bytecode.Pop, 0)
-			case *ast.MemberExpr:
-				if err := c.compileExpr(value, true); err != nil {
-					return err
-				}
-				c.add(bytecode.SetField, c.addConstant(lhs.Name.Value))
-			}
+		if node.Value != nil {
+			c.setLocal(local)
 		}
 
+	case *ast.AssignStmt:
+		return c.compileAssignStmt(node)
+
 	case *ast.WhileStmt:
 		return c.compileWhileStmt(node)
 
@@ -327,11 +433,17 @@ func (c *compiler) compileStmt(stmt ast.Stmt) error {
 		return c.compileForStmt(node)
 
 	case *ast.SwitchStmt:
-		return c.compileSwitchStmt(node)
+		return c.compileSwitchStmt(node, false)
 
 	case *ast.ObjectDecl:
 		return c.compileObjectDecl(node)
 
+	case *ast.EnumDecl:
+		return c.compileEnumDecl(node)
+
+	case *ast.RecordDecl:
+		return c.compileRecordDecl(node)
+
 	case *ast.FunDecl:
 		return c.compileFunDecl(node)
 
@@ -348,14 +460,99 @@ func (c *compiler) compileStmt(stmt ast.Stmt) error {
 		return c.compileNextStmt(node)
 
 	case *ast.IfStmt:
-		return c.compileIfStmt(node)
+		return c.compileIfStmt(node, false)
+	}
+
+	return nil
+}
+
+/*
+Top-level constants are defined when the code runs and can be
+read anywhere after it, methods and files loaded with use included
+*/
+func (c *compiler) compileConstDecl(node *ast.ConstDecl) error {
+	tok := node.Name.Token
+	if c.scope != TOP_SCOPE {
+		return fmt.Errorf("[Lin: %d Col: %d] constants can only be declared at the top level", tok.Line(), tok.Column())
+	}
+
+	if !node.Name.IsConstant() {
+		return fmt.Errorf("[Lin: %d Col: %d] constant '%s' must start with an uppercase letter", tok.Line(), tok.Column(), node.Name)
+	}
+
+	if err := c.compileExpr(node.Value, true); err != nil {
+		return err
+	}
+
+	c.add(bytecode.SetConstant, c.addConstant(node.Name.Value))
+	return nil
+}
+
+/*
+The enum and its members are built while compiling, the values
+associated to the members have to be literals for that reason
+*/
+func (c *compiler) compileEnumDecl(node *ast.EnumDecl) error {
+	tok := node.Token
+	if c.scope != TOP_SCOPE {
+		return fmt.Errorf("[Lin: %d Col: %d] enums can only be declared at the top level", tok.Line(), tok.Column())
+	}
+
+	names := make([]string, len(node.Members))
+	values := make([]lang.IrObject, len(node.Members))
+	for i, member := range node.Members {
+		tok := member.Name.Token
+		for _, name := range names[:i] {
+			if name == member.Name.Value {
+				return fmt.Errorf("[Lin: %d Col: %d] enum %s declares %s twice", tok.Line(), tok.Column(), node.Name, name)
+			}
+		}
+
+		names[i] = member.Name.Value
+		values[i] = lang.None
+		if member.Value == nil {
+			continue
+		}
+
+		lit, ok := member.Value.(*ast.BasicLit)
+		if !ok {
+			return fmt.Errorf("[Lin: %d Col: %d] the value of %s.%s must be a literal", tok.Line(), tok.Column(), node.Name, member.Name)
+		}
+
+		value, err := literalValue(lit)
+		if err != nil {
+			return err
+		}
+
+		values[i] = value
 	}
 
+	c.enums[node.Name.Value] = names
+	c.add(bytecode.DefineEnum, c.addConstant(lang.NewEnum(node.Name.Value, names, values)))
 	return nil
 }
 
+/*
+Returns the enum and member named by node
+*/
+func enumMember(node *ast.MemberExpr) (string, string, bool) {
+	base, ok := node.Base.(*ast.Ident)
+	if !ok || node.Safe || !base.IsConstant() || !node.Name.IsConstant() {
+		return "", "", false
+	}
+
+	return base.Value, node.Name.Value, true
+}
+
 func (c *compiler) compileExpr(expr ast.Expr, isEvaluated bool) error {
 	switch node := expr.(type) {
+	case *ast.Global:
+		// read even when not evaluated, an undefined global fails
+		c.add(bytecode.GetGlobal, c.addConstant(node.Value))
+		if !isEvaluated {
+			c.add(bytecode.Pop, 0)
+		}
+
 	case *ast.Ident:
 		if node.IsConstant() {
 			c.add(bytecode.GetConstant, c.addConstant(node.Value))
@@ -363,16 +560,13 @@ func (c *compiler) compileExpr(expr ast.Expr, isEvaluated bool) error {
 		}
 
 		if local := c.resolve(node.Value); local != nil {
-			if !local.initialized {
-				return errors.New("underfined " + local.name)
-			}
-
-			c.add(bytecode.GetLocal, local.index)
+			c.readLocal(local, node)
 			return nil
 		}
 
 		c.add(bytecode.PushThis, 0)
 		ci := lang.NewCallInfo(node.Value, 0)
+		ci.MarkImplicit()
 		c.add(bytecode.CallMethod, c.addConstant(ci))
 
 	case *ast.BasicLit:
@@ -403,6 +597,32 @@ func (c *compiler) compileExpr(expr ast.Expr, isEvaluated bool) error {
 	case *ast.MapLit:
 		return c.compileHashLit(node)
 
+	case *ast.ArrayComp:
+		err := c.compileComprehension(node.Clause, bytecode.BuildArray, "push", node.Element)
+		if err != nil {
+			return err
+		}
+
+		if !isEvaluated {
+			c.add(bytecode.Pop, 0)
+		}
+
+	case *ast.HashComp:
+		// a bare identifier key is a symbol, every entry would share it
+		if ident, ok := node.Entry.Key.(*ast.Ident); ok {
+			tok := ident.Token
+			return fmt.Errorf("[Lin: %d Col: %d] the key %s would be the symbol :%s for every entry, write (%s) to use its value", tok.Line(), tok.Column(), ident.Value, ident.Value, ident.Value)
+		}
+
+		err := c.compileComprehension(node.Clause, bytecode.BuildHash, "set", node.Entry.Key, node.Entry.Value)
+		if err != nil {
+			return err
+		}
+
+		if !isEvaluated {
+			c.add(bytecode.Pop, 0)
+		}
+
 	case *ast.GroupExpr:
 		return c.compileExpr(node.Expr, isEvaluated)
 
@@ -425,27 +645,10 @@ func (c *compiler) compileExpr(expr ast.Expr, isEvaluated bool) error {
 		}
 
 	case *ast.CallExpr:
-		var methodName string
-		switch fun := node.Function.(type) {
-		case *ast.Ident:
-			c.add(bytecode.PushThis, 0)
-			methodName = fun.Value
-		case *ast.MemberExpr:
-			if err := c.compileExpr(fun.Base, true); err != nil {
-				return err
-			}
-
-			methodName = fun.Name.Value
-		}
-
-		for _, arg := range node.Arguments {
-			if err := c.compileExpr(arg, true); err != nil {
-				return err
-			}
+		if err := c.compileCall(node, 0); err != nil {
+			return err
 		}
 
-		ci := lang.NewCallInfo(methodName, byte(len(node.Arguments)))
-		c.add(bytecode.CallMethod, c.addConstant(ci))
 		if !isEvaluated {
 			c.add(bytecode.Pop, 0)
 		}
@@ -455,12 +658,9 @@ func (c *compiler) compileExpr(expr ast.Expr, isEvaluated bool) error {
 
 		var ci lang.IrObject
 		if node.ExplicitArgs {
-			ci = lang.NewCallInfo(c.name, byte(len(node.Arguments)))
-
-			for _, arg := range node.Arguments {
-				if err := c.compileExpr(arg, true); err != nil {
-					return err
-				}
+			var err error
+			if ci, err = c.compileArgs(c.name, node.Arguments); err != nil {
+				return err
 			}
 		} else {
 			ci = lang.NewCallInfo(c.name, byte(len(c.paramIndices)))
@@ -476,8 +676,35 @@ func (c *compiler) compileExpr(expr ast.Expr, isEvaluated bool) error {
 		}
 
 	case *ast.MemberExpr:
+		if enum, member, ok := enumMember(node); ok {
+			c.add(bytecode.GetConstant, c.addConstant(enum+"."+member))
+			return nil
+		}
+
+		if node.Safe {
+			// fields are only reachable through this, base?.name calls the method
+			return c.compileExpr(&ast.CallExpr{Function: node}, isEvaluated)
+		}
+
 		c.add(bytecode.GetField, c.addConstant(node.Name.Value))
 
+	case *ast.IfExpr:
+		return c.compileIfStmt(node.Stmt, isEvaluated)
+
+	case *ast.SwitchExpr:
+		return c.compileSwitchStmt(node.Stmt, isEvaluated)
+
+	case *ast.CondExpr:
+		return c.compileCondExpr(node, isEvaluated)
+
+	case *ast.SplatExpr:
+		tok := node.Star
+		return fmt.Errorf("[Lin: %d Col: %d] %s can only be used in calls, array and hash literals", tok.Line(), tok.Column(), tok)
+
+	case *ast.PinExpr:
+		tok := node.Caret
+		return fmt.Errorf("[Lin: %d Col: %d] %s can only be used in case patterns", tok.Line(), tok.Column(), node)
+
 	default:
 		return errors.New("unknown expr: " + expr.String())
 	}
@@ -491,6 +718,9 @@ func (c *compiler) addUnary(t *token.Token) {
 }
 
 func (c *compiler) compileBlock(block *ast.BlockStmt, addReturn bool) error {
+	c.enterBlock()
+	defer c.leaveBlock()
+
 	for _, stmt := range block.Stmts {
 		if err := c.compileStmt(stmt); err != nil {
 			return err
@@ -507,97 +737,429 @@ func (c *compiler) compileBlock(block *ast.BlockStmt, addReturn bool) error {
 }
 
 /*
-* CFG for the following snippet
-*
-* a = 100
-* while a > 0 {
-*   puts(a)
-*
-*   a = a - 1
-* }
-*
-*      ┌───────────────────────────────────────────────┐
-* ┌────►  0000 PUSH                100                 │
-* │    │  0002 SET_LOCAL           a@0                 │
-* │    │  0004 GET_LOCAL           a@0                 │
-* │    │  0006 PUSH                0                   │
-* │    │  0008 CALL_METHOD         name: > argc: 1     │
-* │    │  0010 JUMP_IF_FALSE       30                  ├────┐
-* │    └──────────────────────┬────────────────────────┘    │
-* │                           │                             │
-* │                          next                           │
-* │                           │                             │
-* │    ┌──────────────────────▼────────────────────────┐    │
-* │    │  0012 PUSH_THIS                               │    │
-* │    │  0014 GET_LOCAL           a@0                 │    │
-* │    │  0016 CALL_METHOD         name: puts argc: 1  │    │
-* │    │  0018 POP                                     │    │
-* │    │  0020 GET_LOCAL           a@0                 │    │
-* │    │  0022 PUSH                1                   │    │
-* │    │  0024 CALL_METHOD         name: - argc: 1     │    │
-* │    │  0026 SET_LOCAL           a@0                 │    │
-* └────┤  0028 JUMP                4                   │    │
-*      └──────────────────────┬────────────────────────┘    │
-*                             │                             │
-*                            next                           │
-*                             │                             │
-*      ┌──────────────────────▼────────────────────────┐    │
-*      │  0030 PUSH_NONE                               ◄────┘
-*      │  0032 RETURN                                  │
-*      └───────────────────────────────────────────────┘
-* */
+Assignments come in three shapes:
+
+	a = 1          single target, stored as usual
+	a, b = b, a    parallel, all values are evaluated before any store
+	a, b... = list destructuring of a single value into the targets
+
+Parallel values are stored from the last target to the first one,
+as that is the order they are popped off the stack. Targets can be
+array literals, destructuring the value stored into them
+*/
+func (c *compiler) compileAssignStmt(node *ast.AssignStmt) error {
+	if op, ok := token.CompoundOperator(node.Token.Type); ok {
+		return c.compileCompoundAssign(node, op)
+	}
 
-func (c *compiler) compileWhileStmt(node *ast.WhileStmt) error {
-	cond := new(basicblock)
-	loop := new(basicblock)
-	exit := new(basicblock)
+	switch {
+	case len(node.Left) == 1 && len(node.Right) == 1:
+		target, value := node.Left[0], node.Right[0]
 
-	c.pushControlFlow(WHILE_LOOP, cond, exit)
-	defer c.popControlFlow()
+		switch lhs := target.(type) {
+		case *ast.Ident:
+			if isWildcard(lhs) {
+				break
+			}
 
-	c.useBlock(cond)
-	if err := c.compileConditional(node.Cond, exit); err != nil {
-		return err
-	}
+			local := c.defineLocal(lhs)
+			if err := c.compileExpr(value, true); err != nil {
+				return err
+			}
+			c.setLocal(local)
+			return nil
 
-	c.useBlock(loop)
-	if err := c.compileBlock(node.Body, false); err != nil {
-		return err
-	}
+		case *ast.IndexExpr:
+			if err := c.compileExpr(lhs.Expr, true); err != nil {
+				return err
+			}
 
-	c.addJump(bytecode.Jump, cond)
-	c.useBlock(exit)
+			if err := c.compileExpr(lhs.Index, true); err != nil {
+				return err
+			}
 
-	return nil
-}
+			if err := c.compileExpr(value, true); err != nil {
+				return err
+			}
 
-/*
-* CFG for the following snippet
-*
-* for el in [1,2,3] {
-*   puts(el)
-* }
-*
-*
-*       ┌──────────────────────────────────────────────┐
-* ┌─────►  0000 PUSH               1                   │
-* │     │  0002 PUSH               2                   │
-* │     │  0004 PUSH               3                   │
-* │     │  0006 BUILD_ARRAY        size: 3             │
-* │     │  0012 NEWITERATOR                            │
-* │     │  0014 ITERATE                                │
-* │     │  0016 JUMP_IF_FALSE      30                  ├────┐
-* │     └─────────────────────┬────────────────────────┘    │
-* │                           │                             │
-* │                          next                           │
-* │                           │                             │
-* │     ┌─────────────────────▼────────────────────────┐    │
-* │     │  0018 SET_LOCAL          el@1                │    │
-* │     │  0020 PUSH_THIS                              │    │
-* │     │  0022 GET_LOCAL          el@1                │    │
-* │     │  0024 CALL_METHOD        name: puts argc: 1  │    │
-* │     │  0026 POP                                    │    │
-* └─────┤  0028 JUMP               14                  │    │
+			ci := lang.NewCallInfo("set", 2)
+			c.add(bytecode.CallMethod, c.addConstant(ci))
+			c.add(bytecode.Pop, 0)
+			return nil
+		}
+
+		if err := c.compileExpr(value, true); err != nil {
+			return err
+		}
+
+		return c.compileAssignTarget(target)
+
+	case len(node.Right) == 1:
+		if call, ok := directResults(node.Left, node.Right[0]); ok {
+			if err := c.compileCall(call, byte(len(node.Left))); err != nil {
+				return err
+			}
+
+			for _, target := range node.Left {
+				if err := c.compileAssignTarget(target); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		if err := c.compileExpr(node.Right[0], true); err != nil {
+			return err
+		}
+
+		return c.compileDestructure(node.Left)
+
+	case len(node.Left) == len(node.Right):
+		for _, target := range node.Left {
+			if ident, ok := target.(*ast.Ident); ok && !isWildcard(ident) {
+				c.defineLocal(ident)
+			}
+		}
+
+		for _, value := range node.Right {
+			if err := c.compileExpr(value, true); err != nil {
+				return err
+			}
+		}
+
+		for i := len(node.Left) - 1; i >= 0; i-- {
+			if err := c.compileAssignTarget(node.Left[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("[Lin: %d Col: %d] assignment mismatch: %d targets but %d values", node.Token.Line(), node.Token.Column(), len(node.Left), len(node.Right))
+}
+
+/*
+Compiles a += 1 as a = a + 1. The receiver and index of an index
+target are evaluated once and duplicated for the call to get
+*/
+func (c *compiler) compileCompoundAssign(node *ast.AssignStmt, op token.Type) error {
+	if len(node.Left) != 1 || len(node.Right) != 1 {
+		return fmt.Errorf("[Lin: %d Col: %d] %s needs a single target and value", node.Token.Line(), node.Token.Column(), node.Token)
+	}
+
+	target, value := node.Left[0], node.Right[0]
+	apply := func() error {
+		if err := c.compileExpr(value, true); err != nil {
+			return err
+		}
+
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo(binaryOps[op], 1)))
+		return nil
+	}
+
+	switch lhs := target.(type) {
+	case *ast.Ident:
+		local := c.resolve(lhs.Value)
+		if local == nil {
+			return errors.New("underfined " + lhs.Value)
+		}
+
+		c.readLocal(local, lhs)
+		if err := apply(); err != nil {
+			return err
+		}
+		c.setLocal(local)
+
+	case *ast.Global:
+		name := c.addConstant(lhs.Value)
+		c.add(bytecode.GetGlobal, name)
+		if err := apply(); err != nil {
+			return err
+		}
+		c.add(bytecode.SetGlobal, name)
+
+	case *ast.MemberExpr:
+		name := c.addConstant(lhs.Name.Value)
+		c.add(bytecode.GetField, name)
+		if err := apply(); err != nil {
+			return err
+		}
+		c.add(bytecode.SetField, name)
+
+	case *ast.IndexExpr:
+		if err := c.compileExpr(lhs.Expr, true); err != nil {
+			return err
+		}
+
+		if err := c.compileExpr(lhs.Index, true); err != nil {
+			return err
+		}
+
+		c.add(bytecode.Dup, 2)
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("get", 1)))
+		if err := apply(); err != nil {
+			return err
+		}
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("set", 2)))
+		c.add(bytecode.Pop, 0)
+
+	default:
+		return errors.New("cannot assign to " + target.String())
+	}
+
+	return nil
+}
+
+/*
+Compiles a method call, results is the number of values the
+caller destructures straight from it, or 0 for a single value
+*/
+func (c *compiler) compileCall(node *ast.CallExpr, results byte) error {
+	var methodName string
+	var end *basicblock
+	_, implicit := node.Function.(*ast.Ident)
+	switch fun := node.Function.(type) {
+	case *ast.Ident:
+		c.add(bytecode.PushThis, 0)
+		methodName = fun.Value
+	case *ast.MemberExpr:
+		if err := c.compileExpr(fun.Base, true); err != nil {
+			return err
+		}
+
+		if fun.Safe {
+			end = new(basicblock)
+			c.addJump(bytecode.JumpIfNone, end)
+		}
+
+		methodName = fun.Name.Value
+	}
+
+	ci, err := c.compileArgs(methodName, node.Arguments)
+	if err != nil {
+		return err
+	}
+
+	ci.ExpectResults(results)
+	if implicit {
+		ci.MarkImplicit()
+	}
+	c.add(bytecode.CallMethod, c.addConstant(ci))
+	if end != nil {
+		c.useBlock(end)
+	}
+
+	return nil
+}
+
+/*
+A plain call destructured straight into its targets leaves
+the values on the stack instead of returning them in a Tuple
+to be unpacked
+*/
+func directResults(targets []ast.Expr, value ast.Expr) (*ast.CallExpr, bool) {
+	call, ok := value.(*ast.CallExpr)
+	if !ok || len(targets) > 255 {
+		return nil, false
+	}
+
+	if member, ok := call.Function.(*ast.MemberExpr); ok && member.Safe {
+		return nil, false
+	}
+
+	for _, target := range targets {
+		if _, ok := target.(*ast.RestExpr); ok {
+			return nil, false
+		}
+	}
+
+	return call, true
+}
+
+/*
+Unpacks the value on top of the stack into targets, a trailing
+rest target collects the elements left into an array
+*/
+func (c *compiler) compileDestructure(targets []ast.Expr) error {
+	last := len(targets) - 1
+	for i, target := range targets {
+		if _, ok := target.(*ast.RestExpr); ok && i != last {
+			return errors.New("only the last target can collect the rest")
+		}
+	}
+
+	if rest, ok := targets[last].(*ast.RestExpr); ok {
+		c.add(bytecode.UnpackRest, byte(last))
+		targets = append(targets[:last:last], rest.Expr)
+	} else {
+		c.add(bytecode.UnpackArray, byte(len(targets)))
+	}
+
+	for _, target := range targets {
+		if err := c.compileAssignTarget(target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+Stores the value on top of the stack into target
+*/
+func (c *compiler) compileAssignTarget(target ast.Expr) error {
+	switch lhs := target.(type) {
+	case *ast.Ident:
+		if isWildcard(lhs) {
+			c.add(bytecode.Pop, 0)
+			return nil
+		}
+
+		c.setLocal(c.defineLocal(lhs))
+
+	case *ast.IndexExpr:
+		value := c.defineTemp()
+		c.setLocal(value)
+
+		if err := c.compileExpr(lhs.Expr, true); err != nil {
+			return err
+		}
+
+		if err := c.compileExpr(lhs.Index, true); err != nil {
+			return err
+		}
+
+		c.add(bytecode.GetLocal, value.index)
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("set", 2)))
+		c.add(bytecode.Pop, 0)
+
+	case *ast.MemberExpr:
+		c.add(bytecode.SetField, c.addConstant(lhs.Name.Value))
+
+	case *ast.ArrayLit:
+		if len(lhs.Elements) == 0 {
+			return errors.New("cannot destructure into an empty array")
+		}
+
+		return c.compileDestructure(lhs.Elements)
+
+	case *ast.Global:
+		c.add(bytecode.SetGlobal, c.addConstant(lhs.Value))
+
+	case *ast.RestExpr:
+		return errors.New("rest targets can only be used when destructuring a single value")
+
+	default:
+		return errors.New("cannot assign to " + target.String())
+	}
+
+	return nil
+}
+
+/*
+* CFG for the following snippet
+*
+* a = 100
+* while a > 0 {
+*   puts(a)
+*
+*   a = a - 1
+* }
+*
+*      ┌───────────────────────────────────────────────┐
+* ┌────►  0000 PUSH                100                 │
+* │    │  0002 SET_LOCAL           a@0                 │
+* │    │  0004 GET_LOCAL           a@0                 │
+* │    │  0006 PUSH                0                   │
+* │    │  0008 CALL_METHOD         name: > argc: 1     │
+* │    │  0010 JUMP_IF_FALSE       30                  ├────┐
+* │    └──────────────────────┬────────────────────────┘    │
+* │                           │                             │
+* │                          next                           │
+* │                           │                             │
+* │    ┌──────────────────────▼────────────────────────┐    │
+* │    │  0012 PUSH_THIS                               │    │
+* │    │  0014 GET_LOCAL           a@0                 │    │
+* │    │  0016 CALL_METHOD         name: puts argc: 1  │    │
+* │    │  0018 POP                                     │    │
+* │    │  0020 GET_LOCAL           a@0                 │    │
+* │    │  0022 PUSH                1                   │    │
+* │    │  0024 CALL_METHOD         name: - argc: 1     │    │
+* │    │  0026 SET_LOCAL           a@0                 │    │
+* └────┤  0028 JUMP                4                   │    │
+*      └──────────────────────┬────────────────────────┘    │
+*                             │                             │
+*                            next                           │
+*                             │                             │
+*      ┌──────────────────────▼────────────────────────┐    │
+*      │  0030 PUSH_NONE                               ◄────┘
+*      │  0032 RETURN                                  │
+*      └───────────────────────────────────────────────┘
+* */
+
+func (c *compiler) compileWhileStmt(node *ast.WhileStmt) error {
+	cond := new(basicblock)
+	loop := new(basicblock)
+	exit := new(basicblock)
+
+	if err := c.pushControlFlow(WHILE_LOOP, node.Label, cond, exit); err != nil {
+		return err
+	}
+	defer c.popControlFlow()
+
+	// a loop on a literal true is only left by a stop or a return
+	c.useBlock(cond)
+	if !isLiteralTrue(node.Cond) {
+		if err := c.compileConditional(node.Cond, exit); err != nil {
+			return err
+		}
+	}
+
+	c.useBlock(loop)
+	if err := c.compileBlock(node.Body, false); err != nil {
+		return err
+	}
+
+	c.addJump(bytecode.Jump, cond)
+	c.useBlock(exit)
+
+	return nil
+}
+
+func isLiteralTrue(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Type() == token.Bool && lit.Value == "true"
+}
+
+/*
+* CFG for the following snippet
+*
+* for el in [1,2,3] {
+*   puts(el)
+* }
+*
+*
+*       ┌──────────────────────────────────────────────┐
+* ┌─────►  0000 PUSH               1                   │
+* │     │  0002 PUSH               2                   │
+* │     │  0004 PUSH               3                   │
+* │     │  0006 BUILD_ARRAY        size: 3             │
+* │     │  0012 NEWITERATOR                            │
+* │     │  0014 ITERATE                                │
+* │     │  0016 JUMP_IF_FALSE      30                  ├────┐
+* │     └─────────────────────┬────────────────────────┘    │
+* │                           │                             │
+* │                          next                           │
+* │                           │                             │
+* │     ┌─────────────────────▼────────────────────────┐    │
+* │     │  0018 SET_LOCAL          el@1                │    │
+* │     │  0020 PUSH_THIS                              │    │
+* │     │  0022 GET_LOCAL          el@1                │    │
+* │     │  0024 CALL_METHOD        name: puts argc: 1  │    │
+* │     │  0026 POP                                    │    │
+* └─────┤  0028 JUMP               14                  │    │
 *       └─────────────────────┬────────────────────────┘    │
 *                             │                             │
 *                            next                           │
@@ -613,7 +1175,9 @@ func (c *compiler) compileForStmt(node *ast.ForStmt) error {
 	setup := new(basicblock)
 	loop := new(basicblock)
 
-	c.pushControlFlow(FOR_LOOP, loop, exit)
+	if err := c.pushControlFlow(FOR_LOOP, node.Label, loop, exit); err != nil {
+		return err
+	}
 	defer c.popControlFlow()
 
 	c.useBlock(setup)
@@ -622,12 +1186,39 @@ func (c *compiler) compileForStmt(node *ast.ForStmt) error {
 	}
 	c.add(bytecode.NewIterator, 0)
 
+	// loop variables are only visible in the body
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	targets := []*ast.Ident{node.Element}
+	if node.Value != nil {
+		targets = append(targets, node.Value)
+	}
+
+	locals := make([]*local, len(targets))
+	for i, target := range targets {
+		var err error
+		if locals[i], err = c.declareBinding(target); err != nil {
+			return err
+		}
+	}
+
 	c.useBlock(loop)
 	c.add(bytecode.Iterate, 0)
 	c.addJump(bytecode.JumpIfFalse, exit)
 
-	local := c.defineLocal(node.Element, true)
-	c.add(bytecode.SetLocal, local.index)
+	if node.Value != nil {
+		c.add(bytecode.UnpackArray, 2)
+	}
+
+	for _, l := range locals {
+		if l == nil {
+			c.add(bytecode.Pop, 0)
+			continue
+		}
+
+		c.setLocal(l)
+	}
 
 	if err := c.compileBlock(node.Body, false); err != nil {
 		return err
@@ -652,95 +1243,581 @@ func (c *compiler) compileForStmt(node *ast.ForStmt) error {
 *          │  0000 PUSH                10                  │
 *          │  0002 SET_LOCAL           n@0                 │
 *          │  0004 GET_LOCAL           n@0                 │
-*          │  0006 PUSH                10                  │
-*          │  0008 CALL_METHOD         name: == argc: 1    │
-*          │  0010 JUMP_IF_FALSE       22                  ├───┐
+*          │  0006 SET_LOCAL           %tmp1@1             │
+*          │  0008 GET_LOCAL           %tmp1@1             │
+*          │  0010 PUSH                10                  │
+*          │  0012 CALL_METHOD         name: == argc: 1    │
+*          │  0014 JUMP_IF_FALSE       26                  ├───┐
 *          └─────────────────────┬─────────────────────────┘   │
 *                                │                             │
 *                               next                           │
 *                                │                             │
 *          ┌─────────────────────▼─────────────────────────┐   │
-*          │  0012 PUSH_THIS                               │   │
-*          │  0014 PUSH                10                  │   │
-*          │  0016 CALL_METHOD         name: puts argc: 1  │   │
-*          │  0018 POP                                     │   │
-* ┌────────┤  0020 JUMP                48                  │   │
+*          │  0016 PUSH_THIS                               │   │
+*          │  0018 PUSH                10                  │   │
+*          │  0020 CALL_METHOD         name: puts argc: 1  │   │
+*          │  0022 POP                                     │   │
+* ┌────────┤  0024 JUMP                52                  │   │
 * │        └─────────────────────┬─────────────────────────┘   │
 * │                              │                             │
 * │                             next                           │
 * │                              │                             │
 * │        ┌─────────────────────▼─────────────────────────┐   │
-* │        │  0022 GET_LOCAL           n@0                 ◄───┘
-* │        │  0024 PUSH                20                  │
-* │        │  0026 CALL_METHOD         name: == argc: 1    │
-* │        │  0028 JUMP_IF_FALSE       40                  ├───┐
+* │        │  0026 GET_LOCAL           %tmp1@1             ◄───┘
+* │        │  0028 PUSH                20                  │
+* │        │  0030 CALL_METHOD         name: == argc: 1    │
+* │        │  0032 JUMP_IF_FALSE       44                  ├───┐
 * │        └─────────────────────┬─────────────────────────┘   │
 * │                              │                             │
 * │                             next                           │
 * │                              │                             │
 * │        ┌─────────────────────▼─────────────────────────┐   │
-* │        │  0030 PUSH_THIS                               │   │
-* │        │  0032 PUSH                20                  │   │
-* │        │  0034 CALL_METHOD         name: puts argc: 1  │   │
-* │        │  0036 POP                                     │   │
-* │  ┌─────┤  0038 JUMP                48                  │   │
+* │        │  0034 PUSH_THIS                               │   │
+* │        │  0036 PUSH                20                  │   │
+* │        │  0038 CALL_METHOD         name: puts argc: 1  │   │
+* │        │  0040 POP                                     │   │
+* │  ┌─────┤  0042 JUMP                52                  │   │
 * │  │     └─────────────────────┬─────────────────────────┘   │
 * │  │                           │                             │
 * │  │                          next                           │
 * │  │                           │                             │
 * │  │     ┌─────────────────────▼─────────────────────────┐   │
-* │  │     │  0040 PUSH_THIS                               ◄───┘
-* │  │     │  0042 PUSH                "DEFAULT"           │
-* │  │     │  0044 CALL_METHOD         name: puts argc: 1  │
-* │  │     │  0046 POP                                     │
+* │  │     │  0044 PUSH_THIS                               ◄───┘
+* │  │     │  0046 PUSH                "DEFAULT"           │
+* │  │     │  0048 CALL_METHOD         name: puts argc: 1  │
+* │  │     │  0050 POP                                     │
 * │  │     └─────────────────────┬─────────────────────────┘
 * │  │                           │
 * │  │                          next
 * │  │                           │
 * │  │     ┌─────────────────────▼─────────────────────────┐
-* └──┴─────►  0048 PUSH_NONE                               │
-*          │  0050 RETURN                                  │
+* └──┴─────►  0052 PUSH_NONE                               │
+*          │  0054 RETURN                                  │
 *          └───────────────────────────────────────────────┘
 **/
 
-func (c *compiler) compileSwitchStmt(node *ast.SwitchStmt) error {
+func (c *compiler) compileSwitchStmt(node *ast.SwitchStmt, valued bool) error {
 	endBlock := new(basicblock)
 
+	if err := c.compileExpr(node.Key, true); err != nil {
+		return err
+	}
+
+	key := c.defineTemp()
+	c.setLocal(key)
+
+	bodies := make([]*basicblock, len(node.Cases))
+	for i := range bodies {
+		bodies[i] = new(basicblock)
+	}
+
+	defaultBlock := endBlock
+	if node.Default != nil || valued {
+		defaultBlock = new(basicblock)
+	}
+
+	tabled, err := c.compileJumpTable(node, key, bodies, defaultBlock)
+	if err != nil {
+		return err
+	}
+
+	reach := newReachability()
 	lenCases := len(node.Cases) - 1
 	for i, caseClause := range node.Cases {
+		if !reach.check(caseClause) {
+			c.warn(caseClause.Token, "unreachable case")
+		}
+
+		// the locals a case binds are only visible in its body
+		c.enterBlock()
 		nextCase := new(basicblock)
+		if tabled {
+			c.useBlock(bodies[i])
+		} else if err := c.compileCase(caseClause, key, bodies[i], nextCase); err != nil {
+			return err
+		}
 
-		if err := c.compileExpr(node.Key, true); err != nil {
+		if err := c.compileBranch(caseClause.Body, valued); err != nil {
 			return err
 		}
+		c.leaveBlock()
+
+		if i != lenCases || defaultBlock != endBlock {
+			c.addJump(bytecode.Jump, endBlock)
+		}
+
+		c.useBlock(nextCase)
+	}
+
+	if err := c.checkEnumCoverage(node, reach); err != nil {
+		return err
+	}
+
+	if node.Default != nil {
+		if reach.exhausted {
+			c.warn(node.Default.Token, "unreachable default")
+		}
 
-		if err := c.compileExpr(caseClause.Value, true); err != nil {
+		c.useBlock(defaultBlock)
+		if err := c.compileBranch(node.Default.Body, valued); err != nil {
 			return err
 		}
+	} else if valued {
+		c.useBlock(defaultBlock)
+		c.add(bytecode.PushNone, 0)
+	}
+
+	c.useBlock(endBlock)
+	return nil
+}
+
+/*
+Fails when the cases match members of an enum declared in the file
+but miss some of them and there is no default to fall back to
+*/
+func (c *compiler) checkEnumCoverage(node *ast.SwitchStmt, reach *reachability) error {
+	if node.Default != nil || reach.exhausted {
+		return nil
+	}
+
+	for _, caseClause := range node.Cases {
+		for _, pattern := range caseClause.Patterns {
+			member, ok := pattern.(*ast.MemberExpr)
+			if !ok {
+				continue
+			}
+
+			enum, _, ok := enumMember(member)
+			members, declared := c.enums[enum]
+			if !ok || !declared || reach.types[enum] {
+				continue
+			}
+
+			var missing []string
+			for _, name := range members {
+				if !reach.members[enum+"."+name] {
+					missing = append(missing, name)
+				}
+			}
+
+			if len(missing) == 0 {
+				return nil
+			}
+
+			tok := node.Token
+			return fmt.Errorf("[Lin: %d Col: %d] switch over %s misses %s, add them or a default", tok.Line(), tok.Column(), enum, strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+const minJumpTableSize = 4
+
+/*
+Switches whose cases only match Int and String literals are
+compiled into a JUMP_TABLE instead of a linear sequence of tests.
+The table jumps straight into the case body or to miss, any other
+key is compared with == against the literals at runtime. Reports
+whether the table was emitted
+*/
+func (c *compiler) compileJumpTable(node *ast.SwitchStmt, key *local, bodies []*basicblock, miss *basicblock) (bool, error) {
+	table := &jumptable{miss: miss, object: lang.NewJumpTable()}
+
+	for i, caseClause := range node.Cases {
+		if caseClause.Guard != nil {
+			return false, nil
+		}
+
+		for _, pattern := range caseClause.Patterns {
+			value, ok, err := tableKey(pattern)
+			if err != nil {
+				return false, err
+			}
+
+			if !ok {
+				return false, nil
+			}
+
+			table.keys = append(table.keys, value)
+			table.targets = append(table.targets, bodies[i])
+		}
+	}
+
+	if len(table.keys) < minJumpTableSize {
+		return false, nil
+	}
+
+	c.add(bytecode.GetLocal, key.index)
+	c.add(bytecode.JumpTable, c.addConstant(table.object))
+	c.block.instrs[len(c.block.instrs)-1].table = table
+	return true, nil
+}
+
+/*
+Returns the value of an Int or String literal pattern,
+negative Int literals included
+*/
+func tableKey(pattern ast.Expr) (lang.IrObject, bool, error) {
+	negative := false
+	if unary, ok := pattern.(*ast.UnaryExpr); ok {
+		if unary.Operator.Type != token.Minus {
+			return nil, false, nil
+		}
+
+		negative = true
+		pattern = unary.Expr
+	}
+
+	lit, ok := pattern.(*ast.BasicLit)
+	if !ok || lit.Type() != token.Int && (negative || lit.Type() != token.String) {
+		return nil, false, nil
+	}
+
+	value, err := literalValue(lit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch v := value.(type) {
+	case lang.Int:
+		if negative {
+			return -v, true, nil
+		}
+
+		return v, true, nil
+	case *lang.String:
+		return v, true, nil
+	}
+
+	return nil, false, nil
+}
+
+/*
+Compiles the patterns and guard of a case, falling through
+into the case body on a match and jumping to fail otherwise
+*/
+func (c *compiler) compileCase(node *ast.CaseClause, key *local, body, fail *basicblock) error {
+	last := len(node.Patterns) - 1
+	for i, pattern := range node.Patterns {
+		if last > 0 && bindsLocals(pattern) {
+			tok := node.Token
+			return fmt.Errorf("[Lin: %d Col: %d] cannot bind variables in alternative patterns", tok.Line(), tok.Column())
+		}
+
+		if i == last {
+			if err := c.compilePattern(pattern, key, fail); err != nil {
+				return err
+			}
+
+			break
+		}
+
+		nextPattern := new(basicblock)
+		if err := c.compilePattern(pattern, key, nextPattern); err != nil {
+			return err
+		}
+
+		c.addJump(bytecode.Jump, body)
+		c.useBlock(nextPattern)
+	}
+
+	c.useBlock(body)
+	if node.Guard != nil {
+		if err := c.compileExpr(node.Guard, true); err != nil {
+			return err
+		}
+
+		c.addJump(bytecode.JumpIfFalse, fail)
+	}
+
+	return nil
+}
+
+/*
+Compiles the test of pattern against the value in subject,
+binding locals on the way and jumping to fail when it does
+not match
+*/
+func (c *compiler) compilePattern(pattern ast.Expr, subject *local, fail *basicblock) error {
+	switch node := pattern.(type) {
+	case *ast.Ident:
+		if isWildcard(node) {
+			return nil
+		}
+
+		if node.IsConstant() {
+			return c.compileCaseEqual(pattern, subject, fail)
+		}
+
+		binding, err := c.declarePatternBinding(node)
+		if err != nil {
+			return err
+		}
+
+		c.add(bytecode.GetLocal, subject.index)
+		c.setLocal(binding)
+		return nil
+
+	case *ast.BasicLit, *ast.UnaryExpr:
+		return c.compileValueEqual(pattern, subject, fail)
+
+	case *ast.PinExpr:
+		return c.compileValueEqual(node.Name, subject, fail)
+
+	case *ast.ArrayLit:
+		c.matchType("Array", subject, fail)
+
+		c.add(bytecode.GetLocal, subject.index)
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("size", 0)))
+		c.add(bytecode.Push, c.addConstant(len(node.Elements)))
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("==", 1)))
+		c.addJump(bytecode.JumpIfFalse, fail)
+
+		for i, element := range node.Elements {
+			if err := c.compileSubPattern(element, subject, lang.Int(i), fail); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case *ast.MapLit:
+		c.matchType("Hash", subject, fail)
+
+		for _, entry := range node.Entries {
+			key, err := c.patternKey(entry.Key)
+			if err != nil {
+				return err
+			}
+
+			c.add(bytecode.GetLocal, subject.index)
+			c.add(bytecode.Push, c.addConstant(key))
+			c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("key?", 1)))
+			c.addJump(bytecode.JumpIfFalse, fail)
+
+			if err := c.compileSubPattern(entry.Value, subject, key, fail); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return c.compileCaseEqual(pattern, subject, fail)
+	}
+}
+
+/*
+Returns the key looked up by a hash pattern entry, a bare
+identifier is the symbol of its name: {status: s} looks up :status
+*/
+func (c *compiler) patternKey(key ast.Expr) (lang.IrObject, error) {
+	switch node := key.(type) {
+	case *ast.Ident:
+		return lang.Intern(node.Value), nil
+	case *ast.BasicLit:
+		return literalValue(node)
+	default:
+		return nil, errors.New("hash pattern keys must be literals")
+	}
+}
+
+/*
+Matches pattern against subject.get(index), the element is only
+stored in a temporary local when it has to be further matched
+*/
+func (c *compiler) compileSubPattern(pattern ast.Expr, subject *local, index lang.IrObject, fail *basicblock) error {
+	ident, isIdent := pattern.(*ast.Ident)
+	if isIdent && isWildcard(ident) {
+		return nil
+	}
+
+	c.add(bytecode.GetLocal, subject.index)
+	c.add(bytecode.Push, c.addConstant(index))
+	c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("get", 1)))
+
+	if isIdent && !ident.IsConstant() {
+		binding, err := c.declarePatternBinding(ident)
+		if err != nil {
+			return err
+		}
+
+		c.setLocal(binding)
+		return nil
+	}
+
+	element := c.defineTemp()
+	c.setLocal(element)
+	return c.compilePattern(pattern, element, fail)
+}
+
+/*
+Binds a name in a pattern, warning when it hides a local
+whose value the pattern was probably meant to match
+*/
+func (c *compiler) declarePatternBinding(ident *ast.Ident) (*local, error) {
+	if l := c.resolve(ident.Value); l != nil && !l.param && !isWildcard(ident) {
+		mesg := fmt.Sprintf("'%s' shadows a local, use ^%s to match its value", ident.Value, ident.Value)
+		c.warn(ident.Token, mesg)
+	}
+
+	return c.declareBinding(ident)
+}
+
+/*
+Matches with subject == value
+*/
+func (c *compiler) compileValueEqual(value ast.Expr, subject *local, fail *basicblock) error {
+	c.add(bytecode.GetLocal, subject.index)
+	if err := c.compileExpr(value, true); err != nil {
+		return err
+	}
+
+	c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("==", 1)))
+	c.addJump(bytecode.JumpIfFalse, fail)
+	return nil
+}
+
+/*
+Matches with pattern === subject, which checks the type for
+classes, inclusion for ranges and equality for anything else
+*/
+func (c *compiler) compileCaseEqual(pattern ast.Expr, subject *local, fail *basicblock) error {
+	if err := c.compileExpr(pattern, true); err != nil {
+		return err
+	}
+
+	c.add(bytecode.GetLocal, subject.index)
+	c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("===", 1)))
+	c.addJump(bytecode.JumpIfFalse, fail)
+	return nil
+}
+
+func (c *compiler) matchType(name string, subject *local, fail *basicblock) {
+	c.add(bytecode.GetConstant, c.addConstant(name))
+	c.add(bytecode.GetLocal, subject.index)
+	c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("===", 1)))
+	c.addJump(bytecode.JumpIfFalse, fail)
+}
+
+func isWildcard(ident *ast.Ident) bool {
+	return ident.Value == "_"
+}
+
+func bindsLocals(pattern ast.Expr) bool {
+	switch node := pattern.(type) {
+	case *ast.Ident:
+		return !isWildcard(node) && !node.IsConstant()
+	case *ast.ArrayLit:
+		for _, element := range node.Elements {
+			if bindsLocals(element) {
+				return true
+			}
+		}
+	case *ast.MapLit:
+		for _, entry := range node.Entries {
+			if bindsLocals(entry.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/*
+Tracks what the unguarded cases seen so far already
+match, to find cases that can never be reached
+*/
+type reachability struct {
+	exhausted bool
+	literals  map[string]bool
+	types     map[string]bool
+	members   map[string]bool // of enums, keyed by Enum.Member
+}
+
+func newReachability() *reachability {
+	return &reachability{
+		literals: make(map[string]bool),
+		types:    make(map[string]bool),
+		members:  make(map[string]bool),
+	}
+}
+
+/*
+Reports whether the case can still match, and records
+the patterns of the case when it has no guard
+*/
+func (r *reachability) check(node *ast.CaseClause) bool {
+	if r.exhausted {
+		return false
+	}
+
+	reachable := false
+	for _, pattern := range node.Patterns {
+		if !r.covered(pattern) {
+			reachable = true
+		}
+	}
 
-		callInfo := lang.NewCallInfo("==", 1)
-		c.add(bytecode.CallMethod, c.addConstant(callInfo))
+	if node.Guard != nil {
+		return reachable
+	}
 
-		c.addJump(bytecode.JumpIfFalse, nextCase)
-		if err := c.compileBlock(caseClause.Body, false); err != nil {
-			return err
+	for _, pattern := range node.Patterns {
+		switch p := pattern.(type) {
+		case *ast.Ident:
+			if p.IsConstant() {
+				r.types[p.Value] = true
+			} else {
+				r.exhausted = true
+			}
+		case *ast.BasicLit:
+			r.literals[literalKey(p)] = true
+		case *ast.MemberExpr:
+			if enum, member, ok := enumMember(p); ok {
+				r.members[enum+"."+member] = true
+			}
 		}
+	}
 
-		if i != lenCases || node.Default != nil {
-			c.addJump(bytecode.Jump, endBlock)
-		}
+	return reachable
+}
 
-		c.useBlock(nextCase)
+func (r *reachability) covered(pattern ast.Expr) bool {
+	switch p := pattern.(type) {
+	case *ast.Ident:
+		return p.IsConstant() && r.types[p.Value]
+	case *ast.BasicLit:
+		return r.literals[literalKey(p)] || r.types[literalType(p)]
+	case *ast.BinaryExpr:
+		isRange := p.Operator.Type == token.DotDot || p.Operator.Type == token.DotDotDot
+		// a range also matches the floats between its ends
+		return isRange && r.types["Int"] && r.types["Float"]
+	case *ast.MemberExpr:
+		enum, member, ok := enumMember(p)
+		return ok && (r.members[enum+"."+member] || r.types[enum])
 	}
 
-	if node.Default != nil {
-		if err := c.compileBlock(node.Default.Body, false); err != nil {
-			return err
-		}
+	return false
+}
+
+func literalKey(lit *ast.BasicLit) string {
+	return lit.Token.Type.String() + ":" + lit.Value
+}
+
+func literalType(lit *ast.BasicLit) string {
+	switch lit.Token.Type {
+	case token.Int:
+		return "Int"
+	case token.Float:
+		return "Float"
+	case token.String:
+		return "String"
 	}
 
-	c.useBlock(endBlock)
-	return nil
+	return ""
 }
 
 /*
@@ -789,10 +1866,10 @@ func (c *compiler) compileSwitchStmt(node *ast.SwitchStmt) error {
 *         └───────────────────────────────────────────────┘
 * */
 
-func (c *compiler) compileIfStmt(node *ast.IfStmt) error {
+func (c *compiler) compileIfStmt(node *ast.IfStmt, valued bool) error {
 	var elseBlock, endBlock *basicblock
 
-	if node.Else == nil {
+	if node.Else == nil && !valued {
 		endBlock = new(basicblock)
 		elseBlock = endBlock
 	} else {
@@ -804,18 +1881,101 @@ func (c *compiler) compileIfStmt(node *ast.IfStmt) error {
 		return err
 	}
 
-	if err := c.compileBlock(node.Then, false); err != nil {
+	if err := c.compileBranch(node.Then, valued); err != nil {
 		return err
 	}
 
-	if node.Else != nil {
+	if node.Else != nil || valued {
 		c.addJump(bytecode.Jump, endBlock)
 		c.useBlock(elseBlock)
-		if err := c.compileStmt(node.Else); err != nil {
+		if err := c.compileElse(node.Else, valued); err != nil {
+			return err
+		}
+	}
+
+	c.useBlock(endBlock)
+	return nil
+}
+
+func (c *compiler) compileElse(node ast.Stmt, valued bool) error {
+	switch alternative := node.(type) {
+	case nil:
+		c.add(bytecode.PushNone, 0)
+		return nil
+	case *ast.IfStmt:
+		return c.compileIfStmt(alternative, valued)
+	case *ast.BlockStmt:
+		return c.compileBranch(alternative, valued)
+	}
+
+	return c.compileStmt(node)
+}
+
+/*
+Compiles the statements of a branch. A valued branch leaves the
+value of its last statement on the stack, none when the last
+statement is not an expression
+*/
+func (c *compiler) compileBranch(block *ast.BlockStmt, valued bool) error {
+	if !valued {
+		return c.compileBlock(block, false)
+	}
+
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	if control := c.control; control != nil {
+		control.valued++
+		defer func() { control.valued-- }()
+	}
+
+	last := len(block.Stmts) - 1
+	if last < 0 {
+		c.add(bytecode.PushNone, 0)
+		return nil
+	}
+
+	for _, stmt := range block.Stmts[:last] {
+		if err := c.compileStmt(stmt); err != nil {
 			return err
 		}
 	}
 
+	switch stmt := block.Stmts[last].(type) {
+	case *ast.ExprStmt:
+		return c.compileExpr(stmt.Expr, true)
+	case *ast.IfStmt:
+		return c.compileIfStmt(stmt, true)
+	case *ast.SwitchStmt:
+		return c.compileSwitchStmt(stmt, true)
+	}
+
+	if err := c.compileStmt(block.Stmts[last]); err != nil {
+		return err
+	}
+
+	c.add(bytecode.PushNone, 0)
+	return nil
+}
+
+func (c *compiler) compileCondExpr(node *ast.CondExpr, isEvaluated bool) error {
+	elseBlock := new(basicblock)
+	endBlock := new(basicblock)
+
+	if err := c.compileConditional(node.Cond, elseBlock); err != nil {
+		return err
+	}
+
+	if err := c.compileExpr(node.Then, isEvaluated); err != nil {
+		return err
+	}
+
+	c.addJump(bytecode.Jump, endBlock)
+	c.useBlock(elseBlock)
+	if err := c.compileExpr(node.Else, isEvaluated); err != nil {
+		return err
+	}
+
 	c.useBlock(endBlock)
 	return nil
 }
@@ -873,165 +2033,609 @@ func (c *compiler) compileObjectDecl(obj *ast.ObjectDecl) error {
 		}
 	}
 
-	c.add(bytecode.PushNone, 0)
-	c.add(bytecode.Return, 0)
-	objBody := c.assemble()
-	c.closeScope()
-
-	c.add(bytecode.DefineObject, c.addConstant(objBody))
-	c.add(bytecode.Pop, 0)
+	c.add(bytecode.PushNone, 0)
+	c.add(bytecode.Return, 0)
+	objBody, err := c.assemble()
+	if err != nil {
+		return err
+	}
+	c.closeScope()
+
+	c.add(bytecode.DefineObject, c.addConstant(objBody))
+	c.add(bytecode.Pop, 0)
+
+	return nil
+}
+
+/*
+A record is an object inheriting from Record, which compares, hashes
+and inspects it by its fields. The init assigning the fields and a
+reader for each of them are generated, funs in the body may replace
+them
+*/
+func (c *compiler) compileRecordDecl(node *ast.RecordDecl) error {
+	this := &ast.BasicLit{Token: token.New(token.This, "this", node.Token.Position)}
+	init := &ast.FunDecl{
+		Type: &ast.FunctionType{
+			Name:          &ast.Ident{Token: node.Token, Value: "init"},
+			ParameterList: node.FieldList,
+		},
+		Body: new(ast.BlockStmt),
+	}
+
+	obj := &ast.ObjectDecl{
+		Name:         node.Name,
+		Parent:       &ast.Ident{Token: node.Token, Value: "Record"},
+		FunctionList: []*ast.FunDecl{init},
+	}
+
+	for _, field := range node.FieldList {
+		if field.Rest {
+			tok := field.Name.Token
+			return fmt.Errorf("[Lin: %d Col: %d] record field '%s' cannot collect the remaining arguments", tok.Line(), tok.Column(), field.Name)
+		}
+
+		member := &ast.MemberExpr{Base: this, Name: field.Name}
+		obj.FieldList = append(obj.FieldList, &ast.VarDecl{Name: field.Name, Type: field.Type})
+
+		init.Body.Stmts = append(init.Body.Stmts, &ast.AssignStmt{
+			Token: field.Name.Token,
+			Left:  []ast.Expr{member},
+			Right: []ast.Expr{field.Name},
+		})
+
+		obj.FunctionList = append(obj.FunctionList, &ast.FunDecl{
+			Type: &ast.FunctionType{Name: field.Name, Return: field.Type},
+			Body: &ast.BlockStmt{Stmts: []ast.Stmt{
+				&ast.ReturnStmt{Token: field.Name.Token, Results: []ast.Expr{member}},
+			}},
+		})
+	}
+
+	obj.FunctionList = append(obj.FunctionList, node.FunctionList...)
+	return c.compileObjectDecl(obj)
+}
+
+func (c *compiler) compileFunParams(params []*ast.VarDecl) error {
+	var keywords []*ast.VarDecl
+
+	for i, param := range params {
+		if param.Keyword {
+			keywords = append(keywords, param)
+			continue
+		}
+
+		if len(keywords) > 0 {
+			tok := param.Name.Token
+			return fmt.Errorf("[Lin: %d Col: %d] parameter '%s' must come before the keyword parameters", tok.Line(), tok.Column(), param.Name)
+		}
+
+		c.argc++
+		p := c.defineLocal(param.Name)
+		p.param = true
+		c.paramIndices = append(c.paramIndices, p.index)
+
+		if param.Rest {
+			tok := param.Name.Token
+			if i+1 < len(params) && !params[i+1].Keyword {
+				return fmt.Errorf("[Lin: %d Col: %d] rest parameter '%s' must be the last one", tok.Line(), tok.Column(), param.Name)
+			}
+
+			if param.Value != nil {
+				return fmt.Errorf("[Lin: %d Col: %d] rest parameter '%s' can not have a default value", tok.Line(), tok.Column(), param.Name)
+			}
+
+			c.rest = true
+		}
+
+		if param.Value == nil {
+			continue
+		}
+
+		c.optArgc++
+		nextParam := new(basicblock)
+		c.add(bytecode.GetLocal, p.index)
+		c.addJump(bytecode.JumpIfTrue, nextParam)
+
+		if err := c.compileExpr(param.Value, true); err != nil {
+			return err
+		}
+
+		c.setLocal(p)
+		c.useBlock(nextParam)
+	}
+
+	// keyword parameters not given are none until their default replaces it
+	for _, param := range keywords {
+		p := c.defineLocal(param.Name)
+		p.param = true
+		c.keywords = append(c.keywords, lang.KeywordParam{Name: param.Name.Value, Required: param.Value == nil})
+
+		if param.Value == nil {
+			continue
+		}
+
+		given := new(basicblock)
+		c.add(bytecode.GetLocal, p.index)
+		c.addJump(bytecode.JumpIfNotNoneOrPop, given)
+
+		if err := c.compileExpr(param.Value, true); err != nil {
+			return err
+		}
+
+		c.useBlock(given)
+		c.setLocal(p)
+	}
+
+	return nil
+}
+
+func (c *compiler) compileFunDecl(fun *ast.FunDecl) error {
+	if c.scope == FUN_SCOPE {
+		return errors.New("can not declare a method inside of a method")
+	}
+
+	funType := fun.Type
+	c.openScope(funType.Name.Value, FUN_SCOPE)
+
+	if err := c.compileFunParams(funType.ParameterList); err != nil {
+		return err
+	}
+
+	var catch *basicblock
+	if len(fun.Catches) != 0 {
+		catch = new(basicblock)
+		c.addJump(bytecode.WithCatch, catch)
+	}
+
+	if err := c.compileBlock(fun.Body, true); err != nil {
+		return err
+	}
+
+	if catch != nil {
+		c.useBlock(catch)
+		for _, ch := range fun.Catches {
+			catch = new(basicblock)
+
+			c.add(bytecode.MatchType, c.addConstant(ch.Type.Value))
+			c.addJump(bytecode.JumpIfFalse, catch)
+
+			c.enterBlock()
+			if ch.Ref != nil {
+				local, err := c.declareBinding(ch.Ref)
+				if err != nil {
+					return err
+				}
+
+				c.setLocal(local)
+			}
+
+			if err := c.compileBlock(ch.Body, true); err != nil {
+				return err
+			}
+			c.leaveBlock()
+
+			c.useBlock(catch)
+		}
+
+		c.add(bytecode.Throw, 0)
+		c.block.hasReturn = true
+	}
+
+	method, err := c.assemble()
+	if err != nil {
+		return err
+	}
+	c.closeScope()
+	c.add(bytecode.DefineFunction, c.addConstant(method))
+	return nil
+}
+
+func (c *compiler) compileArrayLit(node *ast.ArrayLit) error {
+	return c.compileElements(node.Elements)
+}
+
+/*
+Pushes the arguments of a call, when any of them is a splat they
+are all collected into a single array the interpreter spreads.
+Keyword arguments go last, their names are kept in the call info
+*/
+func (c *compiler) compileArgs(name string, args []ast.Expr) (*lang.CallInfo, error) {
+	var positional []ast.Expr
+	var keywords []*ast.KeywordArg
+	for _, arg := range args {
+		kw, ok := arg.(*ast.KeywordArg)
+		if !ok {
+			if len(keywords) > 0 {
+				tok := keywords[len(keywords)-1].Colon
+				return nil, fmt.Errorf("[Lin: %d Col: %d] positional argument after keyword arguments", tok.Line(), tok.Column())
+			}
+
+			positional = append(positional, arg)
+			continue
+		}
+
+		for _, other := range keywords {
+			if other.Name.Value == kw.Name.Value {
+				tok := kw.Name.Token
+				return nil, fmt.Errorf("[Lin: %d Col: %d] keyword argument '%s' repeated", tok.Line(), tok.Column(), kw.Name)
+			}
+		}
+
+		keywords = append(keywords, kw)
+	}
+
+	splat := false
+	for _, arg := range positional {
+		if _, ok := arg.(*ast.SplatExpr); ok {
+			splat = true
+			break
+		}
+	}
+
+	argc := byte(len(positional))
+	if splat {
+		if err := c.compileElements(positional); err != nil {
+			return nil, err
+		}
+
+		argc = 1
+	} else {
+		for _, arg := range positional {
+			if err := c.compileExpr(arg, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(keywords) == 0 {
+		if splat {
+			return lang.NewSplatCallInfo(name), nil
+		}
+
+		return lang.NewCallInfo(name, argc), nil
+	}
+
+	names := make([]string, len(keywords))
+	for i, kw := range keywords {
+		if err := c.compileExpr(kw.Value, true); err != nil {
+			return nil, err
+		}
+
+		names[i] = kw.Name.Value
+	}
+
+	return lang.NewKeywordCallInfo(name, argc+byte(len(names)), splat, names), nil
+}
+
+/*
+Builds an array out of elements, runs of plain elements are built
+into arrays that get concatenated with the spread ones onto a
+fresh array, so the spread arrays are never modified
+
+	[1, *a, 2, 3]
+
+	0000 PUSH              1
+	0002 BUILD_ARRAY       size: 1
+	0004 GET_LOCAL         a@0
+	0006 PUSH              2
+	0008 PUSH              3
+	0010 BUILD_ARRAY       size: 2
+	0012 CALL_METHOD       name: concat argc: 2
+*/
+func (c *compiler) compileElements(elements []ast.Expr) error {
+	var size byte  // plain elements not built into an array yet
+	var parts byte // arrays to be concatenated onto the first one
+	built := false
+
+	for _, el := range elements {
+		splat, ok := el.(*ast.SplatExpr)
+		if !ok {
+			if err := c.compileExpr(el, true); err != nil {
+				return err
+			}
+
+			size++
+			continue
+		}
+
+		if !built || size > 0 {
+			c.add(bytecode.BuildArray, size)
+			if built {
+				parts++
+			}
+
+			built = true
+			size = 0
+		}
+
+		if err := c.compileExpr(splat.Expr, true); err != nil {
+			return err
+		}
+		parts++
+	}
+
+	if !built || size > 0 {
+		c.add(bytecode.BuildArray, size)
+		if built {
+			parts++
+		}
+	}
+
+	if parts > 0 {
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("concat", parts)))
+	}
 
 	return nil
 }
 
-func (c *compiler) compileFunParams(params []*ast.VarDecl) error {
-	c.argc = byte(len(params))
+/*
+Builds a hash out of its entries, runs of plain entries are built
+into hashes that get merged with the spread ones in order, so the
+later entries win
+
+	{"a": 1, **b}
+
+	0000 PUSH              a
+	0002 PUSH              1
+	0004 BUILD_HASH        size: 2
+	0006 GET_LOCAL         b@0
+	0008 CALL_METHOD       name: merge argc: 1
+*/
+func (c *compiler) compileHashLit(hash *ast.MapLit) error {
+	var size byte
+	built := false
+	merge := func() {
+		c.add(bytecode.CallMethod, c.addConstant(lang.NewCallInfo("merge", 1)))
+	}
 
-	for _, param := range params {
-		p := c.defineLocal(param.Name, true)
-		c.paramIndices = append(c.paramIndices, p.index)
+	for _, entry := range hash.Entries {
+		splat, ok := entry.Key.(*ast.SplatExpr)
+		if !ok {
+			// a bare identifier is a symbol, {name: 1} has the key :name while {(name): 1} reads name
+			if ident, isIdent := entry.Key.(*ast.Ident); isIdent {
+				c.add(bytecode.Push, c.addConstant(lang.Intern(ident.Value)))
+			} else if err := c.compileExpr(entry.Key, true); err != nil {
+				return err
+			}
 
-		if param.Value == nil {
+			if err := c.compileExpr(entry.Value, true); err != nil {
+				return err
+			}
+
+			size += 2
 			continue
 		}
 
-		c.optArgc++
-		nextParam := new(basicblock)
-		c.add(bytecode.GetLocal, p.index)
-		c.addJump(bytecode.JumpIfTrue, nextParam)
+		if !built || size > 0 {
+			c.add(bytecode.BuildHash, size)
+			if built {
+				merge()
+			}
 
-		if err := c.compileExpr(param.Value, true); err != nil {
+			built = true
+			size = 0
+		}
+
+		if err := c.compileExpr(splat.Expr, true); err != nil {
 			return err
 		}
+		merge()
+	}
 
-		c.add(bytecode.SetLocal, p.index)
-		c.useBlock(nextParam)
+	if !built || size > 0 {
+		c.add(bytecode.BuildHash, size)
+		if built {
+			merge()
+		}
 	}
 
 	return nil
 }
 
-func (c *compiler) compileFunDecl(fun *ast.FunDecl) error {
-	if c.scope == FUN_SCOPE {
-		return errors.New("can not declare a method inside of a method")
-	}
+/*
+Compiles a comprehension into the same loop a for statement emits,
+the collection is built in a hidden local and the loop variables
+shadow the enclosing ones until the comprehension ends
+
+	ys = [x * 2 for x in xs if x > 0]
+
+	0006 BUILD_ARRAY       size: 0
+	0008 SET_LOCAL         %tmp2@2
+	0010 GET_LOCAL         xs@0
+	0012 NEWITERATOR
+	0014 ITERATE
+	0016 JUMP_IF_FALSE     42
+	0018 SET_LOCAL         %x@3
+	0020 GET_LOCAL         %x@3
+	0022 PUSH              0
+	0024 CALL_METHOD       name: > argc: 1
+	0026 JUMP_IF_FALSE     14
+	0028 GET_LOCAL         %tmp2@2
+	0030 GET_LOCAL         %x@3
+	0032 PUSH              2
+	0034 CALL_METHOD       name: * argc: 1
+	0036 CALL_METHOD       name: push argc: 1
+	0038 POP
+	0040 JUMP              14
+	0042 GET_LOCAL         %tmp2@2
+	0044 SET_LOCAL         ys@1
+*/
+func (c *compiler) compileComprehension(clause *ast.CompClause, build bytecode.Opcode, method string, args ...ast.Expr) error {
+	exit := new(basicblock)
+	loop := new(basicblock)
 
-	funType := fun.Type
-	c.openScope(funType.Name.Value, FUN_SCOPE)
+	result := c.defineTemp()
+	c.add(build, 0)
+	c.setLocal(result)
 
-	if err := c.compileFunParams(funType.ParameterList); err != nil {
+	// the iterable is evaluated before the loop variables shadow anything
+	if err := c.compileExpr(clause.Iterable, true); err != nil {
 		return err
 	}
+	c.add(bytecode.NewIterator, 0)
 
-	var catch *basicblock
-	if len(fun.Catches) != 0 {
-		catch = new(basicblock)
-		c.addJump(bytecode.WithCatch, catch)
-	}
-
-	if err := c.compileBlock(fun.Body, true); err != nil {
-		return err
+	targets := []ast.Expr{clause.Element}
+	if clause.Value != nil {
+		targets = append(targets, clause.Value)
 	}
 
-	if catch != nil {
-		c.useBlock(catch)
-		for _, ch := range fun.Catches {
-			catch = new(basicblock)
-
-			c.add(bytecode.MatchType, c.addConstant(ch.Type.Value))
-			c.addJump(bytecode.JumpIfFalse, catch)
-
-			if ch.Ref != nil {
-				local := c.defineLocal(ch.Ref, true)
-				c.add(bytecode.SetLocal, local.index)
-			}
-
-			if err := c.compileBlock(ch.Body, true); err != nil {
-				return err
-			}
+	c.enterBlock()
+	defer c.leaveBlock()
 
-			c.useBlock(catch)
+	for _, target := range targets {
+		if _, err := c.declareBinding(target.(*ast.Ident)); err != nil {
+			return err
 		}
-
-		c.add(bytecode.Throw, 0)
-		c.block.hasReturn = true
 	}
 
-	method := c.assemble()
-	c.closeScope()
-	c.add(bytecode.DefineFunction, c.addConstant(method))
-	return nil
-}
+	c.useBlock(loop)
+	c.add(bytecode.Iterate, 0)
+	c.addJump(bytecode.JumpIfFalse, exit)
 
-func (c *compiler) compileArrayLit(node *ast.ArrayLit) error {
-	size := len(node.Elements)
-	for _, el := range node.Elements {
-		if err := c.compileExpr(el, true); err != nil {
+	if len(targets) > 1 {
+		if err := c.compileDestructure(targets); err != nil {
 			return err
 		}
+	} else if err := c.compileAssignTarget(clause.Element); err != nil {
+		return err
 	}
 
-	c.add(bytecode.BuildArray, byte(size))
-	return nil
-}
-
-func (c *compiler) compileHashLit(hash *ast.MapLit) error {
-	size := len(hash.Entries)
-	for _, entry := range hash.Entries {
-		if err := c.compileExpr(entry.Key, true); err != nil {
+	if clause.Cond != nil {
+		if err := c.compileExpr(clause.Cond, true); err != nil {
 			return err
 		}
+		c.addJump(bytecode.JumpIfFalse, loop)
+		c.useBlock(new(basicblock))
+	}
 
-		if err := c.compileExpr(entry.Value, true); err != nil {
+	c.add(bytecode.GetLocal, result.index)
+	for _, arg := range args {
+		if err := c.compileExpr(arg, true); err != nil {
 			return err
 		}
 	}
 
-	c.add(bytecode.BuildHash, byte(size*2))
+	ci := lang.NewCallInfo(method, byte(len(args)))
+	c.add(bytecode.CallMethod, c.addConstant(ci))
+	c.add(bytecode.Pop, 0)
+	c.addJump(bytecode.Jump, loop)
+
+	c.useBlock(exit)
+	c.add(bytecode.GetLocal, result.index)
+
 	return nil
 }
 
 func (c *compiler) compileLiteral(lit *ast.BasicLit) error {
-	var val lang.IrObject
+	switch lit.Type() {
+	case token.None:
+		c.add(bytecode.PushNone, 0)
+		return nil
+
+	case token.This:
+		c.add(bytecode.PushThis, 0)
+		return nil
+	}
+
+	val, err := literalValue(lit)
+	if err != nil {
+		return err
+	}
+
+	c.add(bytecode.Push, c.addConstant(val))
+	return nil
+}
 
+func literalValue(lit *ast.BasicLit) (lang.IrObject, error) {
 	switch lit.Type() {
 	case token.String:
-		val = lang.NewString(lit.Value)
+		return lang.NewString(lit.Value), nil
+
+	case token.Symbol:
+		return lang.Intern(lit.Value), nil
 
 	case token.Bool:
 		value, err := strconv.ParseBool(lit.Value)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		val = lang.Bool(value)
+		return lang.Bool(value), nil
 
 	case token.Int:
-		value, err := strconv.Atoi(lit.Value)
-		if err != nil {
-			return err
-		}
-		val = lang.Int(value)
+		return parseInt(lit.Value)
 
 	case token.Float:
-		value, err := strconv.ParseFloat(lit.Value, 64)
+		value, err := strconv.ParseFloat(strings.ReplaceAll(lit.Value, "_", ""), 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		val = lang.Float(value)
+		return lang.Float(value), nil
 
-	case token.None:
-		c.add(bytecode.PushNone, 0)
-		return nil
+	default:
+		return nil, errors.New("invalid literal")
+	}
+}
 
-	case token.This:
-		c.add(bytecode.PushThis, 0)
-		return nil
+func parseInt(literal string) (lang.IrObject, error) {
+	base := 10
+	digits := strings.ReplaceAll(literal, "_", "")
+
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
 
-	default:
-		return errors.New("invalid literal")
+		if base != 10 {
+			digits = digits[2:]
+		}
 	}
 
-	c.add(bytecode.Push, c.addConstant(val))
+	value, err := strconv.ParseInt(digits, base, 64)
+	if err == nil {
+		return lang.Int(value), nil
+	}
+
+	if !errors.Is(err, strconv.ErrRange) {
+		return nil, err
+	}
+
+	big, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, errors.New("invalid integer literal: " + literal)
+	}
+
+	return lang.NewInteger(big), nil
+}
+
+/*
+Compiles and, or and ?? to yield the operand deciding the result,
+the left one is kept on the stack when it already does so:
+
+	a or b        a ?? b
+	  a             a
+	  JUMP_IF_TRUE_OR_POP end   JUMP_IF_NOT_NONE_OR_POP end
+	  b             b
+	end:          end:
+*/
+func (c *compiler) compileShortCircuit(expr *ast.BinaryExpr, op bytecode.Opcode) error {
+	end := new(basicblock)
+
+	if err := c.compileExpr(expr.Left, true); err != nil {
+		return err
+	}
+
+	c.addJump(op, end)
+	if err := c.compileExpr(expr.Right, true); err != nil {
+		return err
+	}
+
+	c.useBlock(end)
 	return nil
 }
 
@@ -1082,6 +2686,10 @@ func (c *compiler) compileConditional(expr ast.Expr, next *basicblock) error {
 }
 
 func (c *compiler) compileBinaryExpr(expr *ast.BinaryExpr) error {
+	if op, ok := shortCircuitOps[expr.Operator.Type]; ok {
+		return c.compileShortCircuit(expr, op)
+	}
+
 	if err := c.compileExpr(expr.Left, true); err != nil {
 		return err
 	}
@@ -1090,91 +2698,277 @@ func (c *compiler) compileBinaryExpr(expr *ast.BinaryExpr) error {
 		return err
 	}
 
+	switch expr.Operator.Type {
+	case token.DotDot:
+		c.add(bytecode.BuildRange, 0)
+		return nil
+	case token.DotDotDot:
+		c.add(bytecode.BuildRange, 1)
+		return nil
+	case token.In:
+		c.add(bytecode.In, 0)
+		return nil
+	}
+
 	ci := lang.NewCallInfo(binaryOps[expr.Operator.Type], 1)
 	c.add(bytecode.CallMethod, c.addConstant(ci))
 	return nil
 }
 
-func (c *compiler) cleanControlFlow() {
-	if c.control == nil {
-		return
+func (c *compiler) cleanControlFlow(control *controlflow) {
+	if control.loop == FOR_LOOP {
+		c.add(bytecode.Pop, 0)
 	}
+}
 
-	if c.control.loop == FOR_LOOP {
-		c.add(bytecode.Pop, 0)
+/*
+Pops the iterators of the loops being left on the way out to
+target, a nil target leaves every enclosing loop. The loops
+are still open afterwards, the code following a return or a
+stop in the same body keeps resolving them
+*/
+func (c *compiler) rewindControlFlow(target *controlflow) {
+	for control := c.control; control != nil && control != target; control = control.next {
+		c.cleanControlFlow(control)
 	}
 }
 
-func (c *compiler) rewindControlFlow(inLoop bool) {
-	for c.control != nil {
-		switch c.control.loop {
-		case FOR_LOOP, WHILE_LOOP:
-			if inLoop {
-				return
-			}
+/*
+Fails when a stop or next would leave an if or switch whose value
+is being computed, the operands around it would stay on the stack
+*/
+func (c *compiler) checkValuedBranches(tok *token.Token, target *controlflow) error {
+	for control := c.control; control != nil; control = control.next {
+		if control.valued > 0 {
+			return fmt.Errorf("[Lin: %d Col: %d] cannot use %s inside an if or switch used as a value", tok.Line(), tok.Column(), tok.Type)
+		}
+
+		if control == target {
+			break
 		}
+	}
+
+	return nil
+}
+
+/*
+Finds the loop targeted by a stop or next, the innermost
+one when no label is given
+*/
+func (c *compiler) findControlFlow(label *ast.Ident) (*controlflow, error) {
+	if label == nil {
+		return c.control, nil
+	}
 
-		c.cleanControlFlow()
-		c.popControlFlow()
+	for control := c.control; control != nil; control = control.next {
+		if control.label == label.Value {
+			return control, nil
+		}
 	}
+
+	return nil, fmt.Errorf("[Lin: %d Col: %d] unknown loop label '%s'", label.Token.Line(), label.Token.Column(), label.Value)
 }
 
 func (c *compiler) compileReturnStmt(ret *ast.ReturnStmt) error {
-	c.rewindControlFlow(false)
+	c.rewindControlFlow(nil)
+
+	if len(ret.Results) > 255 {
+		return fmt.Errorf("[Lin: %d Col: %d] too many values to return", ret.Token.Line(), ret.Token.Column())
+	}
 
-	if ret.Value != nil {
-		if err := c.compileExpr(ret.Value, true); err != nil {
+	for _, result := range ret.Results {
+		if err := c.compileExpr(result, true); err != nil {
 			return err
 		}
-	} else {
+	}
+
+	if ret.Results == nil {
 		c.add(bytecode.PushNone, 0)
 	}
 
-	c.add(bytecode.Return, 0)
+	// a single value keeps the operand 0, more than one tells how many are on the stack
+	count := 0
+	if len(ret.Results) > 1 {
+		count = len(ret.Results)
+	}
+
+	c.add(bytecode.Return, byte(count))
 	c.block.hasReturn = true
 
 	return nil
 }
 
-func (c *compiler) compileStopStmt(_stop *ast.StopStmt) error {
-	c.rewindControlFlow(true)
+func (c *compiler) compileStopStmt(stop *ast.StopStmt) error {
+	control, err := c.findControlFlow(stop.Label)
+	if err != nil {
+		return err
+	}
 
-	if c.control == nil {
+	if control == nil {
 		return errors.New("STOP OUTSIDE LOOP")
 	}
 
-	c.cleanControlFlow()
-	c.addJump(bytecode.Jump, c.control.exit)
+	if err := c.checkValuedBranches(stop.Token, control); err != nil {
+		return err
+	}
+
+	c.rewindControlFlow(control)
+	c.cleanControlFlow(control)
+	c.addJump(bytecode.Jump, control.exit)
 
 	return nil
 }
 
-func (c *compiler) compileNextStmt(_next *ast.NextStmt) error {
-	c.rewindControlFlow(true)
+func (c *compiler) compileNextStmt(next *ast.NextStmt) error {
+	control, err := c.findControlFlow(next.Label)
+	if err != nil {
+		return err
+	}
 
-	if c.control == nil {
+	if control == nil {
 		return errors.New("NEXT OUTSIDE LOOP")
 	}
 
-	c.addJump(bytecode.Jump, c.control.start)
+	if err := c.checkValuedBranches(next.Token, control); err != nil {
+		return err
+	}
+
+	c.rewindControlFlow(control)
+	c.addJump(bytecode.Jump, control.start)
 
 	return nil
 }
 
-func (c *compiler) defineLocal(ident *ast.Ident, initialized bool) *local {
+/*
+Resolves ident or defines it for the whole function
+when its first assignment is inside a block
+*/
+func (c *compiler) defineLocal(ident *ast.Ident) *local {
 	if l := c.resolve(ident.Value); l != nil {
 		return l
 	}
 
-	index := byte(len(c.locals))
-	l := &local{name: ident.Value, index: index, initialized: initialized}
+	return c.newLocal(ident.Value, 0)
+}
+
+/*
+Declares ident in the current block with var. It shadows
+the locals of the enclosing blocks, parameters included,
+and is released when the block ends
+*/
+func (c *compiler) declareLocal(ident *ast.Ident) (*local, error) {
+	l := c.resolve(ident.Value)
+	if l != nil && l.depth == c.depth {
+		if l.byVar {
+			tok := ident.Token
+			return nil, fmt.Errorf("[Lin: %d Col: %d] '%s' is already declared in this block", tok.Line(), tok.Column(), ident.Value)
+		}
+	} else {
+		l = c.newLocal(ident.Value, c.depth)
+	}
+
+	l.byVar = true
+	return l, nil
+}
+
+/*
+Declares the locals bound by loops, cases and catches. A
+binding shadowing a parameter is most likely a mistake
+*/
+func (c *compiler) declareBinding(ident *ast.Ident) (*local, error) {
+	if isWildcard(ident) {
+		return nil, nil
+	}
+
+	if l := c.resolve(ident.Value); l != nil && l.param {
+		tok := ident.Token
+		return nil, fmt.Errorf("[Lin: %d Col: %d] '%s' shadows a parameter, rename it", tok.Line(), tok.Column(), ident.Value)
+	}
+
+	return c.newLocal(ident.Value, c.depth), nil
+}
+
+/*
+Defines a local that is not visible to the source code,
+used to hold intermediate values
+*/
+func (c *compiler) defineTemp() *local {
+	l := c.newLocal("", c.depth)
+	l.name = fmt.Sprintf("%%tmp%d", l.index)
+	return l
+}
+
+/*
+Function locals take a slot of their own, block locals reuse
+the lowest slot released by a block that already ended
+*/
+func (c *compiler) newLocal(name string, depth int) *local {
+	index := len(c.slots)
+	if depth > 0 {
+		for i, used := range c.slots {
+			if !used {
+				index = i
+				break
+			}
+		}
+	}
+
+	if index == len(c.slots) {
+		c.slots = append(c.slots, true)
+	}
+	c.slots[index] = true
+
+	l := &local{name: name, index: byte(index), id: len(c.declared), depth: depth}
 	c.locals = append(c.locals, l)
+	c.declared = append(c.declared, l)
 	return l
 }
 
-func (c *compiler) resolve(name string) *local {
+func (c *compiler) enterBlock() {
+	c.depth++
+}
+
+/*
+Hides the locals declared in the block and releases their slots
+*/
+func (c *compiler) leaveBlock() {
+	visible := c.locals[:0]
 	for _, l := range c.locals {
-		if l.name == name {
+		if l.depth < c.depth {
+			visible = append(visible, l)
+			continue
+		}
+
+		c.slots[l.index] = false
+	}
+
+	for i := len(visible); i < len(c.locals); i++ {
+		c.locals[i] = nil
+	}
+
+	c.locals = visible
+	c.depth--
+}
+
+/*
+Names of the locals sharing the slot at index, for the disassembler
+*/
+func (f *fragment) slotName(index byte) string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, l := range f.declared {
+		if l.index == index && !seen[l.name] {
+			seen[l.name] = true
+			names = append(names, l.name)
+		}
+	}
+
+	return fmt.Sprintf("%s@%d", strings.Join(names, "|"), index)
+}
+
+func (c *compiler) resolve(name string) *local {
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		if l := c.locals[i]; l.name == name {
 			return l
 		}
 	}
@@ -1196,6 +2990,23 @@ func (c *compiler) add(opcode bytecode.Opcode, operand byte) {
 	c.block.instrs = append(c.block.instrs, ins)
 }
 
+func (c *compiler) setLocal(l *local) {
+	c.add(bytecode.SetLocal, l.index)
+	c.block.instrs[len(c.block.instrs)-1].local = l
+}
+
+/*
+Reads a local named in the source, checked to be assigned
+on every path leading to it before the fragment is assembled
+*/
+func (c *compiler) readLocal(l *local, ident *ast.Ident) {
+	c.add(bytecode.GetLocal, l.index)
+
+	ins := c.block.instrs[len(c.block.instrs)-1]
+	ins.local = l
+	ins.read = ident.Token
+}
+
 func (c *compiler) addConstant(arg interface{}) byte {
 	switch val := arg.(type) {
 	case int:
@@ -1209,22 +3020,26 @@ func (c *compiler) addConstant(arg interface{}) byte {
 	return byte(len(c.consts) - 1)
 }
 
-func (c *compiler) pushControlFlow(loop int, start, exit *basicblock) {
-	if c.control == nil {
-		c.control = &controlflow{
-			loop:  loop,
-			start: start,
-			exit:  exit,
-		}
-		return
-	}
-
-	c.control = &controlflow{
+func (c *compiler) pushControlFlow(loop int, label *ast.Ident, start, exit *basicblock) error {
+	control := &controlflow{
 		loop:  loop,
 		start: start,
 		exit:  exit,
 		next:  c.control,
 	}
+
+	if label != nil {
+		for enclosing := c.control; enclosing != nil; enclosing = enclosing.next {
+			if enclosing.label == label.Value {
+				return fmt.Errorf("[Lin: %d Col: %d] loop label '%s' already used by an enclosing loop", label.Token.Line(), label.Token.Column(), label.Value)
+			}
+		}
+
+		control.label = label.Value
+	}
+
+	c.control = control
+	return nil
 }
 
 func (c *compiler) popControlFlow() {
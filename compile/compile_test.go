@@ -2,21 +2,28 @@ package compile
 
 import (
 	"bytes"
+	"fmt"
+	"iracema/ast"
 	"iracema/bytecode"
 	"iracema/lang"
 	"iracema/parser"
+	"strings"
 	"testing"
 )
 
-func compile(code string) *lang.Method {
+func parse(code string) *ast.File {
 	input := bytes.NewBufferString(code)
 	f, err := parser.Parse(input)
 	if err != nil {
 		panic(err)
 	}
 
+	return f
+}
+
+func compile(code string) *lang.Method {
 	c := New()
-	ins, err := c.Compile(f)
+	ins, err := c.Compile(parse(code))
 	if err != nil {
 		panic(err)
 	}
@@ -165,7 +172,7 @@ func TestCompile_SimpleExpr(t *testing.T) {
 				expect(bytecode.CallMethod).withOperand(0).toBeMethodCall("a", 0),
 				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
 				expect(bytecode.Push).withOperand(2).toHaveConstant(3.1415),
-				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("insert", 2),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("set", 2),
 				expect(bytecode.Pop),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
@@ -243,121 +250,104 @@ func TestCompile_SimpleExpr(t *testing.T) {
 	}
 }
 
-func TestCompileIfStmt(t *testing.T) {
+func TestCompileAssignStmt(t *testing.T) {
 	tests := []struct {
 		Scenario string
 		Code     string
-		Matchs   []Match
+		Matches  []Match
 	}{
 		{
-			Scenario: "compile empty if stmt",
-			Code:     "if 20 > 10 {}",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(4),
+			Scenario: "compile parallel assign",
+			Code:     "a, b = 1, 2\na, b = b, a",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile if with body",
-			Code:     "if 20 > 10 { a = 100 }",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+			Scenario: "compile destructuring assign with rest",
+			Code:     "first, _, rest... = []",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.UnpackRest).toHaveOperand(2),
 				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Pop),
+				expect(bytecode.SetLocal).toHaveOperand(1),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile if with return",
-			Code:     "if 20 < 10 { return 100 }",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("<", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
-				expect(bytecode.Return),
+			Scenario: "compile nested destructuring assign",
+			Code:     "[a, b], c = [], 1",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.UnpackArray).toHaveOperand(2),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.SetLocal).toHaveOperand(2),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile if followed by another stmt",
-			Code:     "if 20 == 10 { a = 100 }\n1 * 1",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+			Scenario: "compile destructuring assign from a call",
+			Code:     "q, _ = divmod(7, 2)",
+			Matches: []Match{
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(7),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("divmod", 2).withResults(2),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Push).withOperand(4).toHaveConstant(1),
-				expect(bytecode.Push).withOperand(5).toHaveConstant(1),
-				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("*", 1),
 				expect(bytecode.Pop),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile if with else",
-			Code:     "if 20 > 10 { x = 100 } else { y = 200 }",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(7),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+			Scenario: "compile destructuring assign with rest from a call",
+			Code:     "q, r... = divmod(7, 2)",
+			Matches: []Match{
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(7),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("divmod", 2),
+				expect(bytecode.UnpackRest).toHaveOperand(1),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Jump).toHaveOperand(9),
-				expect(bytecode.Push).withOperand(4).toHaveConstant(200),
 				expect(bytecode.SetLocal).toHaveOperand(1),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile if with else followed by another stmt",
-			Code:     "if 20 <= 10 { a = 100 } else { b = 200 }\n2 - 1",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("<=", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(7),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+			Scenario: "compile parallel assign to index exprs",
+			Code:     "l = []\nl[0], l[1] = 1, 2",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Jump).toHaveOperand(9),
-				expect(bytecode.Push).withOperand(4).toHaveConstant(200),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
 				expect(bytecode.SetLocal).toHaveOperand(1),
-				expect(bytecode.Push).withOperand(5).toHaveConstant(2),
-				expect(bytecode.Push).withOperand(6).toHaveConstant(1),
-				expect(bytecode.CallMethod).withOperand(7).toBeMethodCall("-", 1),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(2).toHaveConstant(1),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("set", 2),
+				expect(bytecode.Pop),
+				expect(bytecode.SetLocal).toHaveOperand(2),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(4).toHaveConstant(0),
+				expect(bytecode.GetLocal).toHaveOperand(2),
+				expect(bytecode.CallMethod).withOperand(5).toBeMethodCall("set", 2),
 				expect(bytecode.Pop),
-				expect(bytecode.PushNone),
-				expect(bytecode.Return),
-			},
-		},
-
-		{
-			Scenario: "compile if with else with return",
-			Code:     "if 20 <= 10 { return 100 } else { return 200 }",
-			Matchs: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("<=", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
-				expect(bytecode.Return),
-				expect(bytecode.Push).withOperand(4).toHaveConstant(200),
-				expect(bytecode.Return),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
@@ -367,115 +357,66 @@ func TestCompileIfStmt(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Scenario, func(t *testing.T) {
 			fun := compile(test.Code)
-			for i, instr := range fun.Instrs() {
-				test.Matchs[i].Match(t, instr, fun.Constants())
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
 			}
 		})
 	}
 }
 
-func TestCompileWhileStmt(t *testing.T) {
+func TestCompileAssignStmt_withCompoundOperator(t *testing.T) {
 	tests := []struct {
 		Scenario string
 		Code     string
 		Matches  []Match
 	}{
 		{
-			Scenario: "compile while stmt",
-			Code:     "while 100 > 100 {}",
-			Matches: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(100),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(100),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(5),
-				expect(bytecode.Jump).toHaveOperand(0),
-				expect(bytecode.PushNone),
-				expect(bytecode.Return),
-			},
-		},
-		{
-			Scenario: "compile while with body",
-			Code:     "while 140 >= 100 { a = 200 }",
+			Scenario: "compile compound assign to local",
+			Code:     "a = 1\na -= 2",
 			Matches: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(140),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(100),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">=", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(7),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(200),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("-", 1),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Jump).toHaveOperand(0),
-				expect(bytecode.PushNone),
-				expect(bytecode.Return),
-			},
-		},
-		{
-			Scenario: "compile while with return",
-			Code:     "while 140 >= 100 { return 200 }",
-			Matches: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(140),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(100),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">=", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(200),
-				expect(bytecode.Return),
-				expect(bytecode.PushNone),
-				expect(bytecode.Return),
-			},
-		},
-		{
-			Scenario: "compile while with expr",
-			Code:     "while 0 == 0 { 20 / 3 }",
-			Matches: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(0),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(0),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(9),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(20),
-				expect(bytecode.Push).withOperand(4).toHaveConstant(3),
-				expect(bytecode.CallMethod).withOperand(5).toBeMethodCall("/", 1),
-				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(0),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile while with next stmt",
-			Code:     "while 0 == 0 { next }",
+			Scenario: "compile compound assign to field",
+			Code:     "this.total *= 2",
 			Matches: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(0),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(0),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(5),
-				expect(bytecode.Jump).toHaveOperand(0),
+				expect(bytecode.GetField).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("*", 1),
+				expect(bytecode.SetField).withOperand(0).toHaveConstant("total"),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile while with stop stmt",
-			Code: `a = 10
- 				   while a >= 0 {
- 				     if a == 5 { stop }
- 				     a = a - 1
- 				   }`,
+			Scenario: "compile compound assign to index",
+			Code:     "h = {}\nh[key()] += 1",
 			Matches: []Match{
-				expect(bytecode.Push).withOperand(0).toHaveConstant(10),
+				expect(bytecode.BuildHash).toHaveOperand(0),
 				expect(bytecode.SetLocal).toHaveOperand(0),
 				expect(bytecode.GetLocal).toHaveOperand(0),
-				expect(bytecode.Push).withOperand(1).toHaveConstant(0),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">=", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(16),
-				expect(bytecode.GetLocal).toHaveOperand(0),
-				expect(bytecode.Push).withOperand(3).toHaveConstant(5),
-				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(11),
-				expect(bytecode.Jump).toHaveOperand(16),
-				expect(bytecode.GetLocal).toHaveOperand(0),
-				expect(bytecode.Push).withOperand(5).toHaveConstant(1),
-				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("-", 1),
-				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushThis),
+				expect(bytecode.CallMethod).withOperand(0).toBeMethodCall("key", 0),
+				expect(bytecode.Dup).toHaveOperand(2),
+				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("get", 1),
+				expect(bytecode.Push).withOperand(2).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("+", 1),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("set", 2),
+				expect(bytecode.Pop),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
@@ -485,105 +426,841 @@ func TestCompileWhileStmt(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Scenario, func(t *testing.T) {
 			fun := compile(test.Code)
-			for i, instr := range fun.Instrs() {
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
 				test.Matches[i].Match(t, instr, fun.Constants())
 			}
 		})
 	}
 }
 
-func TestCompileForStmt(t *testing.T) {
+func TestCompileAssignStmt_Errors(t *testing.T) {
 	tests := []struct {
 		Scenario string
 		Code     string
-		Matches  []Match
+		Expected string
 	}{
 		{
-			Scenario: "compile for stmt",
-			Code:     "for el in [] {}",
+			Scenario: "more values than targets",
+			Code:     "a, b = 1, 2, 3",
+			Expected: "[Lin: 1 Col: 6] assignment mismatch: 2 targets but 3 values",
+		},
+		{
+			Scenario: "more targets than values",
+			Code:     "a, b, c = 1, 2",
+			Expected: "[Lin: 1 Col: 9] assignment mismatch: 3 targets but 2 values",
+		},
+		{
+			Scenario: "rest not in last position",
+			Code:     "a, b..., c = []",
+			Expected: "only the last target can collect the rest",
+		},
+		{
+			Scenario: "compound assign to undefined local",
+			Code:     "a += 1",
+			Expected: "underfined a",
+		},
+		{
+			Scenario: "compound assign with multiple targets",
+			Code:     "a, b += 1, 2",
+			Expected: "[Lin: 1 Col: 6] += needs a single target and value",
+		},
+		{
+			Scenario: "rest in parallel assign",
+			Code:     "a, b... = 1, 2",
+			Expected: "rest targets can only be used when destructuring a single value",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompile_BlockScopes(t *testing.T) {
+	tests := []struct {
+		Scenario   string
+		Code       string
+		LocalCount byte
+		Matches    []Match
+	}{
+		{
+			Scenario:   "sibling blocks reuse the slot of a var",
+			Code:       "x = 1\nif x { var a = 2 }\nif x { var b = a }",
+			LocalCount: 2,
 			Matches: []Match{
-				expect(bytecode.BuildArray).toHaveOperand(0),
-				expect(bytecode.NewIterator),
-				expect(bytecode.Iterate),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.JumpIfFalse).toHaveOperand(11),
+				expect(bytecode.PushThis),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("a", 0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile for with body",
-			Code:     "for e in [1] { puts(e) }",
+			Scenario:   "var shadows a local until the block ends",
+			Code:       "x = 1\nif x { var x = x }\nx",
+			LocalCount: 2,
 			Matches: []Match{
 				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
-				expect(bytecode.BuildArray).toHaveOperand(1),
-				expect(bytecode.NewIterator),
-				expect(bytecode.Iterate),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario:   "locals first assigned in a block belong to the function",
+			Code:       "for el in [] { var sq = el\ntotal = sq }\ntotal = 0",
+			LocalCount: 3,
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(10),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.SetLocal).toHaveOperand(2),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(0),
+				expect(bytecode.SetLocal).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			if fun.LocalCount() != test.LocalCount {
+				t.Errorf("expected %d locals, got %d", test.LocalCount, fun.LocalCount())
+			}
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected instrs size(%d) to be equal to matchers(%d)", len(instrs), len(test.Matches))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompile_BlockScopes_Errors(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "loop variable shadowing a parameter",
+			Code:     "fun f(n Int) {\n  for n in [] {}\n}",
+			Expected: "[Lin: 2 Col: 7] 'n' shadows a parameter, rename it",
+		},
+		{
+			Scenario: "case binding shadowing a parameter",
+			Code:     "fun f(n Int) {\n  switch [] { case [n]: n }\n}",
+			Expected: "[Lin: 2 Col: 21] 'n' shadows a parameter, rename it",
+		},
+		{
+			Scenario: "var declared twice in a block",
+			Code:     "if true {\n  var a = 1\n  var a = 2\n}",
+			Expected: "[Lin: 3 Col: 7] 'a' is already declared in this block",
+		},
+		{
+			Scenario: "var read after its block",
+			Code:     "if true { var a = 1 }\na += 1",
+			Expected: "underfined a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompile_DefiniteAssignment(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "assigned in both branches",
+			Code:     "if c { x = 1 } else { x = 2 }\nx",
+		},
+		{
+			Scenario: "assigned before the only stop of an endless loop",
+			Code:     "n = 0\nwhile true { w = n; if n > 2 { stop }; n += 1 }\nputs(w)",
+		},
+		{
+			Scenario: "branch returning before the read",
+			Code:     "fun f(c Bool) {\n  if c { return 1 } else { x = 2 }\n  return x\n}",
+		},
+		{
+			Scenario: "parameters are assigned",
+			Code:     "fun f(a Int, b: 1) { return a + b }",
+		},
+		{
+			Scenario: "switch assigning in every case and default",
+			Code:     "var v Int\nswitch 1 { case 1: v = 1\ndefault: v = 2 }\nv",
+		},
+		{
+			Scenario: "assigned in a single branch",
+			Code:     "if c { x = 1 }\nputs(x)",
+			Expected: "[Lin: 2 Col: 6] 'x' is read before being assigned",
+		},
+		{
+			Scenario: "assigned in a loop body",
+			Code:     "while c { y = 1 }\ny",
+			Expected: "[Lin: 2 Col: 1] 'y' is read before being assigned",
+		},
+		{
+			Scenario: "read in its own assignment",
+			Code:     "x = x + 1",
+			Expected: "[Lin: 1 Col: 5] 'x' is read before being assigned",
+		},
+		{
+			Scenario: "var without a value",
+			Code:     "var v Int\nswitch 1 { case 1: v = 1 }\nv",
+			Expected: "[Lin: 3 Col: 1] 'v' is read before being assigned",
+		},
+		{
+			Scenario: "assigned for the next iterations only",
+			Code:     "var last Int\nfor el in [] {\n  if el { puts(last) }\n  last = el\n}",
+			Expected: "[Lin: 3 Col: 16] 'last' is read before being assigned",
+		},
+		{
+			Scenario: "catch reading a local of the body",
+			Code:     "fun f() { z = 1 } catch(err: Error) { puts(z) }",
+			Expected: "[Lin: 1 Col: 44] 'z' is read before being assigned",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+
+			if test.Expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompileIfStmt(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matchs   []Match
+	}{
+		{
+			Scenario: "compile empty if stmt",
+			Code:     "if 20 > 10 {}",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(4),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile if with body",
+			Code:     "if 20 > 10 { a = 100 }",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile if with return",
+			Code:     "if 20 < 10 { return 100 }",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("<", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+				expect(bytecode.Return),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile if followed by another stmt",
+			Code:     "if 20 == 10 { a = 100 }\n1 * 1",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(4).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(5).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("*", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile if with else",
+			Code:     "if 20 > 10 { x = 100 } else { y = 200 }",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(7),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Jump).toHaveOperand(9),
+				expect(bytecode.Push).withOperand(4).toHaveConstant(200),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile if with else followed by another stmt",
+			Code:     "if 20 <= 10 { a = 100 } else { b = 200 }\n2 - 1",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("<=", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(7),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Jump).toHaveOperand(9),
+				expect(bytecode.Push).withOperand(4).toHaveConstant(200),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.Push).withOperand(5).toHaveConstant(2),
+				expect(bytecode.Push).withOperand(6).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(7).toBeMethodCall("-", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+
+		{
+			Scenario: "compile if with else with return",
+			Code:     "if 20 <= 10 { return 100 } else { return 200 }",
+			Matchs: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("<=", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(100),
+				expect(bytecode.Return),
+				expect(bytecode.Push).withOperand(4).toHaveConstant(200),
+				expect(bytecode.Return),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+			for i, instr := range fun.Instrs() {
+				test.Matchs[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompileWhileStmt(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile while stmt",
+			Code:     "while 100 > 100 {}",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(100),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(100),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(5),
+				expect(bytecode.Jump).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile while with body",
+			Code:     "while 140 >= 100 { a = 200 }",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(140),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(100),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">=", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(7),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(200),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Jump).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile while with return",
+			Code:     "while 140 >= 100 { return 200 }",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(140),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(100),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">=", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(200),
+				expect(bytecode.Return),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile while with expr",
+			Code:     "while 0 == 0 { 20 / 3 }",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(0),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(9),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(20),
+				expect(bytecode.Push).withOperand(4).toHaveConstant(3),
+				expect(bytecode.CallMethod).withOperand(5).toBeMethodCall("/", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile while with next stmt",
+			Code:     "while 0 == 0 { next }",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(0),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(5),
+				expect(bytecode.Jump).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile while with stop stmt",
+			Code: `a = 10
+ 				   while a >= 0 {
+ 				     if a == 5 { stop }
+ 				     a = a - 1
+ 				   }`,
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(10),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall(">=", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(16),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(5),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(11),
+				expect(bytecode.Jump).toHaveOperand(16),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(5).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("-", 1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+			for i, instr := range fun.Instrs() {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompileForStmt(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile for stmt",
+			Code:     "for el in [] {}",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for stmt with key and value",
+			Code:     "for k, v in {} {}",
+			Matches: []Match{
+				expect(bytecode.BuildHash).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(8),
+				expect(bytecode.UnpackArray).toHaveOperand(2),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with body",
+			Code:     "for e in [1] { puts(e) }",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.BuildArray).toHaveOperand(1),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(11),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushThis),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(3),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with return",
+			Code:     "for el in [] { return 200 }",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(8),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Pop),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(200),
+				expect(bytecode.Return),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with next stmt",
+			Code:     "for el in [] { next }",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with stop stmt",
+			Code: `for el in [] {
+				     if el == 2 { stop }
+					 puts(el)
+				   }`,
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(16),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("==", 1),
 				expect(bytecode.JumpIfFalse).toHaveOperand(11),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(16),
+				expect(bytecode.PushThis),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with labeled stop stmt",
+			Code:     "outer: for a in [] { for b in [] { stop outer } }",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(14),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(13),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.Pop),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(14),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with labeled next stmt",
+			Code:     "outer: for a in [] { for b in [] { next outer } }",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(13),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(12),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.Jump).toHaveOperand(2),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile for with stop stmt after return",
+			Code:     "for a in [] { if a { return a }; stop }",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(12),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.JumpIfFalse).toHaveOperand(10),
+				expect(bytecode.Pop),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Return),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(12),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+			for i, instr := range fun.Instrs() {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompileLoopLabels_Errors(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "unknown label",
+			Code:     "for a in [] { stop outer }",
+			Expected: "[Lin: 1 Col: 20] unknown loop label 'outer'",
+		},
+		{
+			Scenario: "label of a loop already left",
+			Code:     "outer: for a in [] {}; for b in [] { next outer }",
+			Expected: "[Lin: 1 Col: 43] unknown loop label 'outer'",
+		},
+		{
+			Scenario: "label used by an enclosing loop",
+			Code:     "outer: for a in [] { outer: while true { stop } }",
+			Expected: "[Lin: 1 Col: 22] loop label 'outer' already used by an enclosing loop",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompileControlFlowInValuedBranches(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "next in an if used as an element",
+			Code:     "for v in [1, 2] { puts([v, if v == 2 { next } else { v }]) }",
+			Expected: "[Lin: 1 Col: 40] cannot use next inside an if or switch used as a value",
+		},
+		{
+			Scenario: "stop in a switch used as an argument",
+			Code:     "while true { puts(switch 1 { case 1: stop; default: 2 }) }",
+			Expected: "[Lin: 1 Col: 38] cannot use stop inside an if or switch used as a value",
+		},
+		{
+			Scenario: "labeled next leaving an inner loop",
+			Code:     "outer: for v in [1] { puts(if v { for w in [1] { next outer } } else { 1 }) }",
+			Expected: "[Lin: 1 Col: 50] cannot use next inside an if or switch used as a value",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+
+	// loops inside the branch are left before its value is computed
+	compile("puts(if true { for v in [1] { next }; 1 } else { 2 })")
+}
+
+func TestCompileIfExpr(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile if expr",
+			Code:     "x = if true { 1 } else { 2 }",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(true),
+				expect(bytecode.JumpIfFalse).toHaveOperand(4),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+				expect(bytecode.Jump).toHaveOperand(5),
+				expect(bytecode.Push).withOperand(2).toHaveConstant(2),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.PushThis),
-				expect(bytecode.GetLocal).toHaveOperand(0),
-				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("puts", 1),
-				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(3),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile for with return",
-			Code:     "for el in [] { return 200 }",
+			Scenario: "compile if expr without else",
+			Code:     "x = if true { puts(1) }",
 			Matches: []Match{
-				expect(bytecode.BuildArray).toHaveOperand(0),
-				expect(bytecode.NewIterator),
-				expect(bytecode.Iterate),
-				expect(bytecode.JumpIfFalse).toHaveOperand(8),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(true),
+				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("puts", 1),
+				expect(bytecode.Jump).toHaveOperand(7),
+				expect(bytecode.PushNone),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Pop),
-				expect(bytecode.Push).withOperand(0).toHaveConstant(200),
-				expect(bytecode.Return),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile for with next stmt",
-			Code:     "for el in [] { next }",
+			Scenario: "compile switch expr",
+			Code:     "x = switch 1 { case 1: 2 }",
 			Matches: []Match{
-				expect(bytecode.BuildArray).toHaveOperand(0),
-				expect(bytecode.NewIterator),
-				expect(bytecode.Iterate),
-				expect(bytecode.JumpIfFalse).toHaveOperand(6),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(8),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(2),
+				expect(bytecode.Jump).toHaveOperand(9),
+				expect(bytecode.PushNone),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.Jump).toHaveOperand(2),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
 		},
 		{
-			Scenario: "compile for with stop stmt",
-			Code: `for el in [] {
-				     if el == 2 { stop }
-					 puts(el)
-				   }`,
+			Scenario: "compile cond expr",
+			Code:     "x = true ? 1 : 2",
 			Matches: []Match{
-				expect(bytecode.BuildArray).toHaveOperand(0),
-				expect(bytecode.NewIterator),
-				expect(bytecode.Iterate),
-				expect(bytecode.JumpIfFalse).toHaveOperand(16),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(true),
+				expect(bytecode.JumpIfFalse).toHaveOperand(4),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+				expect(bytecode.Jump).toHaveOperand(5),
+				expect(bytecode.Push).withOperand(2).toHaveConstant(2),
 				expect(bytecode.SetLocal).toHaveOperand(0),
-				expect(bytecode.GetLocal).toHaveOperand(0),
-				expect(bytecode.Push).withOperand(0).toHaveConstant(2),
-				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(11),
-				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(16),
-				expect(bytecode.PushThis),
-				expect(bytecode.GetLocal).toHaveOperand(0),
-				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("puts", 1),
-				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(2),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
 			},
@@ -593,7 +1270,13 @@ func TestCompileForStmt(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Scenario, func(t *testing.T) {
 			fun := compile(test.Code)
-			for i, instr := range fun.Instrs() {
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
 				test.Matches[i].Match(t, instr, fun.Constants())
 			}
 		})
@@ -611,9 +1294,11 @@ func TestCompileSwitchStmt(t *testing.T) {
 			Code:     "switch 10 { case 10: puts(10) }",
 			Matches: []Match{
 				expect(bytecode.Push).toHaveOperand(0).toHaveConstant(10),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
 				expect(bytecode.Push).toHaveOperand(1).toHaveConstant(10),
 				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(8),
+				expect(bytecode.JumpIfFalse).toHaveOperand(10),
 				expect(bytecode.PushThis),
 				expect(bytecode.Push).toHaveOperand(3).toHaveConstant(10),
 				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("puts", 1),
@@ -627,21 +1312,23 @@ func TestCompileSwitchStmt(t *testing.T) {
 			Code:     "switch 10 { case 10: puts(10); case 20: puts(20) }",
 			Matches: []Match{
 				expect(bytecode.Push).toHaveOperand(0).toHaveConstant(10),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
 				expect(bytecode.Push).toHaveOperand(1).toHaveConstant(10),
 				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(9),
+				expect(bytecode.JumpIfFalse).toHaveOperand(11),
 				expect(bytecode.PushThis),
 				expect(bytecode.Push).toHaveOperand(3).toHaveConstant(10),
 				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("puts", 1),
 				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(17),
-				expect(bytecode.Push).toHaveOperand(5).toHaveConstant(10),
-				expect(bytecode.Push).toHaveOperand(6).toHaveConstant(20),
-				expect(bytecode.CallMethod).withOperand(7).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(17),
+				expect(bytecode.Jump).toHaveOperand(19),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(5).toHaveConstant(20),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(19),
 				expect(bytecode.PushThis),
-				expect(bytecode.Push).toHaveOperand(8).toHaveConstant(20),
-				expect(bytecode.CallMethod).withOperand(9).toBeMethodCall("puts", 1),
+				expect(bytecode.Push).toHaveOperand(7).toHaveConstant(20),
+				expect(bytecode.CallMethod).withOperand(8).toBeMethodCall("puts", 1),
 				expect(bytecode.Pop),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
@@ -652,26 +1339,151 @@ func TestCompileSwitchStmt(t *testing.T) {
 			Code:     `switch 10 { case 10: puts(10); case 20: puts(20); default: puts("default") }`,
 			Matches: []Match{
 				expect(bytecode.Push).toHaveOperand(0).toHaveConstant(10),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
 				expect(bytecode.Push).toHaveOperand(1).toHaveConstant(10),
 				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(9),
+				expect(bytecode.JumpIfFalse).toHaveOperand(11),
 				expect(bytecode.PushThis),
 				expect(bytecode.Push).toHaveOperand(3).toHaveConstant(10),
 				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("puts", 1),
 				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(22),
-				expect(bytecode.Push).toHaveOperand(5).toHaveConstant(10),
-				expect(bytecode.Push).toHaveOperand(6).toHaveConstant(20),
-				expect(bytecode.CallMethod).withOperand(7).toBeMethodCall("==", 1),
-				expect(bytecode.JumpIfFalse).toHaveOperand(18),
+				expect(bytecode.Jump).toHaveOperand(24),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(5).toHaveConstant(20),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(20),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).toHaveOperand(7).toHaveConstant(20),
+				expect(bytecode.CallMethod).withOperand(8).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(24),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).toHaveOperand(9).toHaveConstant("default"),
+				expect(bytecode.CallMethod).withOperand(10).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile switch stmt with range case",
+			Code:     "switch 3 { case 1..5: puts(3) }",
+			Matches: []Match{
+				expect(bytecode.Push).toHaveOperand(0).toHaveConstant(3),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(1).toHaveConstant(1),
+				expect(bytecode.Push).toHaveOperand(2).toHaveConstant(5),
+				expect(bytecode.BuildRange).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("===", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(12),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).toHaveOperand(4).toHaveConstant(3),
+				expect(bytecode.CallMethod).withOperand(5).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile switch stmt with alternatives",
+			Code:     "switch 3 { case 1, 2: puts(1) }",
+			Matches: []Match{
+				expect(bytecode.Push).toHaveOperand(0).toHaveConstant(3),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(1).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(7),
+				expect(bytecode.Jump).toHaveOperand(11),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(3).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(15),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).toHaveOperand(5).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile switch stmt with type and guard",
+			Code:     "switch 3 { case Int: puts(1); case x if x: puts(x) }",
+			Matches: []Match{
+				expect(bytecode.Push).toHaveOperand(0).toHaveConstant(3),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetConstant).toHaveOperand(1).toHaveConstant("Int"),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("===", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(11),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).toHaveOperand(3).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(19),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(19),
 				expect(bytecode.PushThis),
-				expect(bytecode.Push).toHaveOperand(8).toHaveConstant(20),
-				expect(bytecode.CallMethod).withOperand(9).toBeMethodCall("puts", 1),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.CallMethod).withOperand(5).toBeMethodCall("puts", 1),
 				expect(bytecode.Pop),
-				expect(bytecode.Jump).toHaveOperand(22),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile switch stmt with array pattern",
+			Code:     "switch [] { case [a, _]: puts(a) }",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetConstant).toHaveOperand(0).toHaveConstant("Array"),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("===", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(19),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("size", 0),
+				expect(bytecode.Push).toHaveOperand(3).toHaveConstant(2),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("==", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(19),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(5).toHaveConstant(0),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("get", 1),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.PushThis),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.CallMethod).withOperand(7).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile switch stmt with hash pattern",
+			Code:     "switch {} { case {status: s}: puts(s) }",
+			Matches: []Match{
+				expect(bytecode.BuildHash).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.GetConstant).toHaveOperand(0).toHaveConstant("Hash"),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("===", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(18),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(2).toHaveConstant(lang.Intern("status")),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("key?", 1),
+				expect(bytecode.JumpIfFalse).toHaveOperand(18),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).toHaveOperand(4).toHaveConstant(lang.Intern("status")),
+				expect(bytecode.CallMethod).withOperand(5).toBeMethodCall("get", 1),
+				expect(bytecode.SetLocal).toHaveOperand(1),
 				expect(bytecode.PushThis),
-				expect(bytecode.Push).toHaveOperand(10).toHaveConstant("default"),
-				expect(bytecode.CallMethod).withOperand(11).toBeMethodCall("puts", 1),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.CallMethod).withOperand(6).toBeMethodCall("puts", 1),
 				expect(bytecode.Pop),
 				expect(bytecode.PushNone),
 				expect(bytecode.Return),
@@ -682,13 +1494,239 @@ func TestCompileSwitchStmt(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Scenario, func(t *testing.T) {
 			fun := compile(test.Code)
-			for i, instr := range fun.Instrs() {
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
 				test.Matches[i].Match(t, instr, fun.Constants())
 			}
 		})
 	}
 }
 
+func TestCompileSwitchStmt_withJumpTable(t *testing.T) {
+	code := `switch 2 { case 1: puts(1); case 2, -3: puts(2); case 4: puts(4); default: puts(0) }`
+	fun := compile(code)
+
+	instrs := fun.Instrs()
+	expect(bytecode.GetLocal).toHaveOperand(0).Match(t, instrs[2], fun.Constants())
+
+	opcode, operand := bytecode.Opcode(instrs[3]>>8), byte(instrs[3]&255)
+	if opcode != bytecode.JumpTable {
+		t.Fatalf("expected bytecode.Opcode to be %s, got %s", bytecode.JumpTable, opcode)
+	}
+
+	table, ok := fun.Constants()[operand].(*lang.JumpTable)
+	if !ok {
+		t.Fatalf("expected constant at %d to be *lang.JumpTable, got %T", operand, fun.Constants()[operand])
+	}
+
+	tests := []struct {
+		Key      lang.IrObject
+		Expected lang.IrObject
+	}{
+		{Key: lang.Int(1), Expected: lang.Int(1)},
+		{Key: lang.Int(2), Expected: lang.Int(2)},
+		{Key: lang.Int(-3), Expected: lang.Int(2)},
+		{Key: lang.Int(4), Expected: lang.Int(4)},
+		{Key: lang.Int(5), Expected: lang.Int(0)},
+		{Key: lang.NewString("1"), Expected: lang.Int(0)},
+	}
+
+	for _, test := range tests {
+		target, ok := table.Lookup(test.Key)
+		if !ok {
+			t.Fatalf("expected %s to be looked up", test.Key)
+		}
+
+		// every body starts with PUSH_THIS followed by the PUSH of its argument
+		expect(bytecode.PushThis).Match(t, instrs[target], fun.Constants())
+		push := instrs[target+1]
+		if value := fun.Constants()[byte(push&255)]; value != test.Expected {
+			t.Errorf("expected %s to jump to puts(%s), got puts(%s)", test.Key, test.Expected, value)
+		}
+	}
+
+	if _, ok := table.Lookup(lang.Float(1)); ok {
+		t.Errorf("expected Float keys to fall through to the case tests")
+	}
+}
+
+func TestCompileSwitchStmt_withLargeJumpTable(t *testing.T) {
+	var code strings.Builder
+	code.WriteString("switch 1 {")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&code, " case %d: puts(%d);", i, i)
+	}
+	code.WriteString(" }")
+
+	fun := compile(code.String())
+	instrs := fun.Instrs()
+	for _, instr := range instrs {
+		if opcode := bytecode.Opcode(instr >> 8); opcode == bytecode.CallMethod {
+			info := fun.Constants()[byte(instr&255)].(*lang.CallInfo)
+			if info.Name() == "==" {
+				t.Fatalf("expected the cases to only be matched by %s", bytecode.JumpTable)
+			}
+		}
+	}
+
+	table := fun.Constants()[byte(instrs[3]&255)].(*lang.JumpTable)
+	for i := 0; i < 50; i++ {
+		target, _ := table.Lookup(lang.Int(i))
+		push := instrs[target+1]
+		if value := fun.Constants()[byte(push&255)]; value != lang.Int(i) {
+			t.Errorf("expected %d to jump to puts(%d), got puts(%s)", i, i, value)
+		}
+	}
+}
+
+func TestCompileSwitchStmt_withJumpsPastTheOperand(t *testing.T) {
+	var code strings.Builder
+	code.WriteString("switch 1 {")
+	for i := 0; i < 70; i++ {
+		fmt.Fprintf(&code, " case %d: puts(1);", i)
+	}
+	code.WriteString(" }")
+
+	_, err := New().Compile(parse(code.String()))
+	if err == nil || err.Error() != "main is too long to compile, jumps cannot reach past instr 255" {
+		t.Errorf("expected a too long error, got %v", err)
+	}
+}
+
+func TestCompileSwitchStmt_withoutJumpTable(t *testing.T) {
+	tests := []string{
+		"switch 2 { case 1: puts(1); case 2: puts(2); case 3: puts(3) }",
+		"switch 2 { case 1: puts(1); case 2: puts(2); case 3: puts(3); case x if x: puts(x) }",
+		"switch 2 { case 1: puts(1); case 2: puts(2); case 3: puts(3); case 4.0: puts(4) }",
+	}
+
+	for _, code := range tests {
+		fun := compile(code)
+		for _, instr := range fun.Instrs() {
+			if opcode := bytecode.Opcode(instr >> 8); opcode == bytecode.JumpTable {
+				t.Errorf("expected %q to be compiled without %s", code, opcode)
+			}
+		}
+	}
+}
+
+func TestCompileSwitchStmt_Warnings(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Warnings []string
+	}{
+		{
+			Scenario: "no warnings",
+			Code:     "switch 1 { case 1: puts(1); case x if x > 1: puts(x); default: puts(0) }",
+		},
+		{
+			Scenario: "repeated literal",
+			Code:     "switch 1 { case 1: puts(1); case 2, 1: puts(2); case 1: puts(3) }",
+			Warnings: []string{"[Lin: 1 Col: 49] warning: unreachable case"},
+		},
+		{
+			Scenario: "after binding",
+			Code:     "switch 1 { case x: puts(x); case 2: puts(2); default: puts(0) }",
+			Warnings: []string{
+				"[Lin: 1 Col: 29] warning: unreachable case",
+				"[Lin: 1 Col: 46] warning: unreachable default",
+			},
+		},
+		{
+			Scenario: "binding shadows a local",
+			Code:     "expected = 1\nswitch 1 { case [expected]: puts(1); case expected: puts(2) }",
+			Warnings: []string{
+				"[Lin: 2 Col: 18] warning: 'expected' shadows a local, use ^expected to match its value",
+				"[Lin: 2 Col: 43] warning: 'expected' shadows a local, use ^expected to match its value",
+			},
+		},
+		{
+			Scenario: "pinned local",
+			Code:     "expected = 1\nswitch 1 { case ^expected: puts(1); case [^expected]: puts(2); default: puts(0) }",
+		},
+		{
+			Scenario: "after type",
+			Code:     "switch 1 { case Int: puts(1); case 2: puts(2); case 1..5: puts(3) }",
+			Warnings: []string{"[Lin: 1 Col: 31] warning: unreachable case"},
+		},
+		{
+			Scenario: "range after both number types",
+			Code:     "switch 1 { case Int, Float: puts(1); case 1..5: puts(2) }",
+			Warnings: []string{"[Lin: 1 Col: 38] warning: unreachable case"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			if _, err := c.Compile(parse(test.Code)); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			warnings := c.Warnings()
+			if len(warnings) != len(test.Warnings) {
+				t.Fatalf("expected %d warnings, got %q", len(test.Warnings), warnings)
+			}
+
+			for i, warning := range test.Warnings {
+				if warnings[i] != warning {
+					t.Errorf("expected warning %q, got %q", warning, warnings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileSwitchStmt_withBindingAlternatives(t *testing.T) {
+	c := New()
+	_, err := c.Compile(parse("switch 1 { case 1, x: puts(x) }"))
+	if err == nil || err.Error() != "[Lin: 1 Col: 12] cannot bind variables in alternative patterns" {
+		t.Errorf("expected alternative binding error, got %v", err)
+	}
+}
+
+func TestCompileSwitchStmt_withPinnedLocal(t *testing.T) {
+	fun := compile("expected = 2\nswitch 1 { case ^expected: puts(1) }")
+
+	matches := []Match{
+		expect(bytecode.Push).withOperand(0).toHaveConstant(2),
+		expect(bytecode.SetLocal).toHaveOperand(0),
+		expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+		expect(bytecode.SetLocal).toHaveOperand(1),
+		expect(bytecode.GetLocal).toHaveOperand(1),
+		expect(bytecode.GetLocal).toHaveOperand(0),
+		expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("==", 1),
+		expect(bytecode.JumpIfFalse).toHaveOperand(12),
+		expect(bytecode.PushThis),
+		expect(bytecode.Push).withOperand(3).toHaveConstant(1),
+		expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("puts", 1),
+		expect(bytecode.Pop),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	instrs := fun.Instrs()
+	if len(instrs) != len(matches) {
+		t.Fatalf("expected %d instructions, got %d", len(matches), len(instrs))
+	}
+
+	for i, instr := range instrs {
+		matches[i].Match(t, instr, fun.Constants())
+	}
+
+	c := New()
+	_, err := c.Compile(parse("puts(^expected)"))
+	if err == nil || err.Error() != "[Lin: 1 Col: 6] ^expected can only be used in case patterns" {
+		t.Errorf("expected pin error, got %v", err)
+	}
+}
+
 func TestCompileObjectDecl_Empty(t *testing.T) {
 	objMatches := []Match{
 		expect(bytecode.PushNone),
@@ -912,6 +1950,438 @@ func TestCompileFunDecl_withDefaultParams(t *testing.T) {
 	}
 }
 
+func TestCompileFunDecl_withKeywordParams(t *testing.T) {
+	funMatch := []Match{
+		expect(bytecode.GetLocal).toHaveOperand(1),
+		expect(bytecode.JumpIfNotNoneOrPop).toHaveOperand(3),
+		expect(bytecode.Push).withOperand(0).toHaveConstant(80),
+		expect(bytecode.SetLocal).toHaveOperand(1),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	top := []Match{
+		expect(bytecode.DefineFunction).toDefine("listen", funMatch),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	fun := compile("fun listen(host String, port: 80, tls:) {}")
+
+	for i, instr := range fun.Instrs() {
+		top[i].Match(t, instr, fun.Constants())
+	}
+
+	method := fun.Constants()[0].(*lang.Method)
+	if method.Arity() != 1 {
+		t.Errorf("expected arity to be 1, got %d", method.Arity())
+	}
+
+	want := []lang.KeywordParam{{Name: "port"}, {Name: "tls", Required: true}}
+	keywords := method.Keywords()
+	if len(keywords) != len(want) {
+		t.Fatalf("expected %d keywords, got %d", len(want), len(keywords))
+	}
+
+	for i, keyword := range keywords {
+		if keyword != want[i] {
+			t.Errorf("expected keyword %d to be %v, got %v", i, want[i], keyword)
+		}
+	}
+}
+
+func TestCompile_KeywordArgs(t *testing.T) {
+	fun := compile("listen(1, tls: true, port: 8080)")
+
+	matches := []Match{
+		expect(bytecode.PushThis),
+		expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+		expect(bytecode.Push).withOperand(1).toHaveConstant(true),
+		expect(bytecode.Push).withOperand(2).toHaveConstant(8080),
+		expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("listen", 3),
+		expect(bytecode.Pop),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	instrs := fun.Instrs()
+	if len(instrs) != len(matches) {
+		t.Fatalf("expected %d instructions, got %d", len(matches), len(instrs))
+	}
+
+	for i, instr := range instrs {
+		matches[i].Match(t, instr, fun.Constants())
+	}
+
+	keywords := fun.Constants()[3].(*lang.CallInfo).Keywords()
+	if len(keywords) != 2 || keywords[0] != "tls" || keywords[1] != "port" {
+		t.Errorf("expected keywords to be [tls port], got %v", keywords)
+	}
+}
+
+func TestCompile_KeywordArgs_Errors(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "positional after keyword",
+			Code:     "listen(port: 80, 1)",
+			Expected: "[Lin: 1 Col: 12] positional argument after keyword arguments",
+		},
+		{
+			Scenario: "repeated keyword",
+			Code:     "listen(port: 80, port: 81)",
+			Expected: "[Lin: 1 Col: 18] keyword argument 'port' repeated",
+		},
+		{
+			Scenario: "positional parameter after keyword parameter",
+			Code:     "fun listen(port: 80, host String) {}",
+			Expected: "[Lin: 1 Col: 22] parameter 'host' must come before the keyword parameters",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompile_ConstantsAndGlobals(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile top-level const",
+			Code:     "const PI = 3.14\nPI",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(3.14),
+				expect(bytecode.SetConstant).withOperand(1).toHaveConstant("PI"),
+				expect(bytecode.GetConstant).withOperand(2).toHaveConstant("PI"),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile global assign and compound assign",
+			Code:     "$count = 0\n$count += 1",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(0),
+				expect(bytecode.SetGlobal).withOperand(1).toHaveConstant("count"),
+				expect(bytecode.GetGlobal).withOperand(2).toHaveConstant("count"),
+				expect(bytecode.Push).withOperand(3).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("+", 1),
+				expect(bytecode.SetGlobal).withOperand(2).toHaveConstant("count"),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile global destructuring and read",
+			Code:     "$a, b = 1, 2\nputs($a)",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.SetGlobal).withOperand(2).toHaveConstant("a"),
+				expect(bytecode.PushThis),
+				expect(bytecode.GetGlobal).withOperand(3).toHaveConstant("a"),
+				expect(bytecode.CallMethod).withOperand(4).toBeMethodCall("puts", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected instrs size(%d) to be equal to matchers(%d)", len(instrs), len(test.Matches))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompile_Constants_Errors(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "const inside a function",
+			Code:     "fun f() {\n  const LIMIT = 1\n}",
+			Expected: "[Lin: 2 Col: 9] constants can only be declared at the top level",
+		},
+		{
+			Scenario: "lowercase const",
+			Code:     "const limit = 1",
+			Expected: "[Lin: 1 Col: 7] constant 'limit' must start with an uppercase letter",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompile_Enums(t *testing.T) {
+	fun := compile("enum Color { Red, Green }\nColor.Green")
+
+	matches := []Match{
+		expect(bytecode.DefineEnum).toHaveOperand(0),
+		expect(bytecode.GetConstant).withOperand(1).toHaveConstant("Color.Green"),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	instrs := fun.Instrs()
+	if len(instrs) != len(matches) {
+		t.Fatalf("expected instrs size(%d) to be equal to matchers(%d)", len(instrs), len(matches))
+	}
+
+	for i, instr := range instrs {
+		matches[i].Match(t, instr, fun.Constants())
+	}
+
+	enum, ok := fun.Constants()[0].(*lang.Class)
+	if !ok || enum.Name() != "Color" || enum.Super() != lang.EnumClass {
+		t.Errorf("expected the enum Color, got %v", fun.Constants()[0])
+	}
+}
+
+func TestCompile_EnumSwitches(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+	}{
+		{
+			Scenario: "every member matched",
+			Code:     "switch c {\ncase Color.Red: 1\ncase Color.Green, Color.Blue: 2\n}",
+		},
+		{
+			Scenario: "missing members with a default",
+			Code:     "switch c {\ncase Color.Red: 1\ndefault: 2\n}",
+		},
+		{
+			Scenario: "matched by type",
+			Code:     "switch c {\ncase Color.Red: 1\ncase Color: 2\n}",
+		},
+		{
+			Scenario: "enum declared elsewhere",
+			Code:     "switch c {\ncase Size.Small: 1\n}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			code := "enum Color { Red, Green, Blue }\nvar c = Color.Red\n" + test.Code
+			if _, err := c.Compile(parse(code)); err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+func TestCompile_Enums_Errors(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "switch missing members",
+			Code:     "enum Color { Red, Green, Blue }\nswitch Color.Red {\ncase Color.Red: 1\ncase Color.Green if true: 2\n}",
+			Expected: "[Lin: 2 Col: 1] switch over Color misses Green, Blue, add them or a default",
+		},
+		{
+			Scenario: "switch in a function missing members",
+			Code:     "enum Color { Red, Green }\nfun paint(c Object) {\n  switch c { case Color.Red: 1 }\n}",
+			Expected: "[Lin: 3 Col: 3] switch over Color misses Green, add them or a default",
+		},
+		{
+			Scenario: "switch after a function missing members",
+			Code:     "enum Color { Red, Green }\nfun f() {}\nswitch Color.Red { case Color.Green: 1 }",
+			Expected: "[Lin: 3 Col: 1] switch over Color misses Red, add them or a default",
+		},
+		{
+			Scenario: "enum inside a function",
+			Code:     "fun f() {\n  enum Color { Red }\n}",
+			Expected: "[Lin: 2 Col: 3] enums can only be declared at the top level",
+		},
+		{
+			Scenario: "member declared twice",
+			Code:     "enum Color { Red, Red }",
+			Expected: "[Lin: 1 Col: 19] enum Color declares Red twice",
+		},
+		{
+			Scenario: "value not a literal",
+			Code:     "enum Status { Ok = code }",
+			Expected: "[Lin: 1 Col: 15] the value of Status.Ok must be a literal",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
+func TestCompile_RecordDecl(t *testing.T) {
+	initMatches := []Match{
+		expect(bytecode.GetLocal).toHaveOperand(0),
+		expect(bytecode.SetField).toHaveOperand(0),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	readerMatches := []Match{
+		expect(bytecode.GetField).withOperand(0).toHaveConstant("x"),
+		expect(bytecode.Return),
+	}
+
+	objMatches := []Match{
+		expect(bytecode.DefineField).withOperand(0).toHaveConstant("x"),
+		expect(bytecode.DefineFunction).toDefine("init", initMatches),
+		expect(bytecode.DefineFunction).toDefine("x", readerMatches),
+		expect(bytecode.DefineFunction).toDefine("norm", []Match{
+			expect(bytecode.PushNone),
+			expect(bytecode.Return),
+		}),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	checkers := []Match{
+		expect(bytecode.GetConstant).withOperand(0).toHaveConstant("Record"),
+		expect(bytecode.DefineObject).toDefine("Point", objMatches),
+		expect(bytecode.Pop),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	fun := compile("record Point(x Int) {\n  fun norm() {}\n}")
+
+	instrs := fun.Instrs()
+	if len(instrs) != len(checkers) {
+		t.Fatalf("expected instrs size(%d) to be equal to matchers(%d)", len(instrs), len(checkers))
+	}
+
+	for i, instr := range instrs {
+		checkers[i].Match(t, instr, fun.Constants())
+	}
+}
+
+func TestCompile_RecordDecl_RestField(t *testing.T) {
+	c := New()
+	_, err := c.Compile(parse("record Args(*parts)"))
+
+	expected := "[Lin: 1 Col: 14] record field 'parts' cannot collect the remaining arguments"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestCompile_ImplicitReceiver(t *testing.T) {
+	tests := []struct {
+		Code     string
+		Implicit bool
+	}{
+		{Code: "helper(1)", Implicit: true},
+		{Code: "helper", Implicit: true},
+		{Code: "this.helper(1)", Implicit: false},
+		{Code: "[].helper()", Implicit: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Code, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			var ci *lang.CallInfo
+			for _, constant := range fun.Constants() {
+				if info, ok := constant.(*lang.CallInfo); ok && info.Name() == "helper" {
+					ci = info
+				}
+			}
+
+			if ci == nil {
+				t.Fatalf("expected a call to helper")
+			}
+
+			if ci.Implicit() != test.Implicit {
+				t.Errorf("expected implicit to be %t, got %t", test.Implicit, ci.Implicit())
+			}
+		})
+	}
+}
+
+func TestCompile_BigIntLiteral(t *testing.T) {
+	fun := compile("a = 123456789012345678901234567890")
+
+	consts := fun.Constants()
+	value, ok := consts[0].(*lang.BigInt)
+	if !ok {
+		t.Fatalf("expected constant to be *lang.BigInt, got %T", consts[0])
+	}
+
+	if value.String() != "123456789012345678901234567890" {
+		t.Errorf("expected constant to be 123456789012345678901234567890, got %s", value)
+	}
+}
+
+func TestCompile_NumericLiterals(t *testing.T) {
+	tests := []struct {
+		Code     string
+		Expected interface{}
+	}{
+		{Code: "a = 0xFF", Expected: 255},
+		{Code: "a = 0b1010", Expected: 10},
+		{Code: "a = 0o755", Expected: 493},
+		{Code: "a = 1_000_000", Expected: 1000000},
+		{Code: "a = 0x_ff_ff", Expected: 65535},
+		{Code: "a = 010", Expected: 10},
+		{Code: "a = 1e-9", Expected: 1e-9},
+		{Code: "a = 2.5E3", Expected: 2500.0},
+		{Code: "a = 1_000.5", Expected: 1000.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Code, func(t *testing.T) {
+			fun := compile(test.Code)
+			match := expect(bytecode.Push).withOperand(0).toHaveConstant(test.Expected)
+			match.Match(t, fun.Instrs()[0], fun.Constants())
+		})
+	}
+}
+
 func TestCompileArrayLit(t *testing.T) {
 	top := []Match{
 		expect(bytecode.Push).withOperand(0).toHaveConstant(1),
@@ -921,9 +2391,37 @@ func TestCompileArrayLit(t *testing.T) {
 		expect(bytecode.Return),
 	}
 
-	fun := compile("[1, 2]")
-	for i, instr := range fun.Instrs() {
-		top[i].Match(t, instr, fun.Constants())
+	fun := compile("[1, 2]")
+	for i, instr := range fun.Instrs() {
+		top[i].Match(t, instr, fun.Constants())
+	}
+}
+
+func TestCompileRangeLit(t *testing.T) {
+	tests := []struct {
+		Code      string
+		Exclusive byte
+	}{
+		{Code: "1..10", Exclusive: 0},
+		{Code: "1...10", Exclusive: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Code, func(t *testing.T) {
+			top := []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(10),
+				expect(bytecode.BuildRange).toHaveOperand(test.Exclusive),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			}
+
+			fun := compile(test.Code)
+			for i, instr := range fun.Instrs() {
+				top[i].Match(t, instr, fun.Constants())
+			}
+		})
 	}
 }
 
@@ -988,6 +2486,30 @@ func TestCompileReturnStmt(t *testing.T) {
 
 }
 
+func TestCompileReturnStmt_withManyValues(t *testing.T) {
+	methMatches := []Match{
+		expect(bytecode.GetLocal).toHaveOperand(1),
+		expect(bytecode.GetLocal).toHaveOperand(0),
+		expect(bytecode.Return).toHaveOperand(2),
+	}
+
+	top := []Match{
+		expect(bytecode.DefineFunction).toDefine("swap", methMatches),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	meth := compile("fun swap(a Int, b Int) { return b, a }")
+	instrs := meth.Instrs()
+	if len(instrs) != len(top) {
+		t.Fatalf("expected instrs size(%d) to be equal to matchers(%d)", len(instrs), len(top))
+	}
+
+	for i, instr := range instrs {
+		top[i].Match(t, instr, meth.Constants())
+	}
+}
+
 func TestCompileReturnStmt_withoutValue(t *testing.T) {
 	methMatches := []Match{
 		expect(bytecode.PushNone),
@@ -1026,6 +2548,350 @@ func TestCompile_LogicalOperator_Or(t *testing.T) {
 	}
 }
 
+func TestCompile_ShortCircuitOperators(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile or",
+			Code:     `name = none or "default"`,
+			Matches: []Match{
+				expect(bytecode.PushNone),
+				expect(bytecode.JumpIfTrueOrPop).toHaveOperand(3),
+				expect(bytecode.Push).withOperand(0).toHaveConstant("default"),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile and",
+			Code:     "x = 1 and 2",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.JumpIfFalseOrPop).toHaveOperand(3),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile none coalescing",
+			Code:     "x = none ?? 2",
+			Matches: []Match{
+				expect(bytecode.PushNone),
+				expect(bytecode.JumpIfNotNoneOrPop).toHaveOperand(3),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(2),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile safe navigation",
+			Code:     "x = none?.get(1)",
+			Matches: []Match{
+				expect(bytecode.PushNone),
+				expect(bytecode.JumpIfNone).toHaveOperand(4),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.CallMethod).withOperand(1).toBeMethodCall("get", 1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile safe navigation without arguments",
+			Code:     "none?.size",
+			Matches: []Match{
+				expect(bytecode.PushNone),
+				expect(bytecode.JumpIfNone).toHaveOperand(3),
+				expect(bytecode.CallMethod).withOperand(0).toBeMethodCall("size", 0),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompile_TypeAndMembershipOperators(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile is",
+			Code:     "x = 1 is Int",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.GetConstant).withOperand(1).toHaveConstant("Int"),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("is_a?", 1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile in",
+			Code:     `x = "a" in "abc"`,
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant("a"),
+				expect(bytecode.Push).withOperand(1).toHaveConstant("abc"),
+				expect(bytecode.In),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompile_Comprehensions(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile array comprehension",
+			Code:     "x = 1\n[x for x in 1..2 if x]\nx",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(2).toHaveConstant(2),
+				expect(bytecode.BuildRange).toHaveOperand(0),
+				expect(bytecode.NewIterator),
+				expect(bytecode.Iterate),
+				expect(bytecode.JumpIfFalse).toHaveOperand(18),
+				expect(bytecode.SetLocal).toHaveOperand(2),
+				expect(bytecode.GetLocal).toHaveOperand(2),
+				expect(bytecode.JumpIfFalse).toHaveOperand(8),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(2),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("push", 1),
+				expect(bytecode.Pop),
+				expect(bytecode.Jump).toHaveOperand(8),
+				expect(bytecode.GetLocal).toHaveOperand(1),
+				expect(bytecode.Pop),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompile_Splat(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Matches  []Match
+	}{
+		{
+			Scenario: "compile splat call",
+			Code:     "a = [2]\nlog(1, *a)",
+			Matches: []Match{
+				expect(bytecode.Push).withOperand(0).toHaveConstant(2),
+				expect(bytecode.BuildArray).toHaveOperand(1),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.PushThis),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(1),
+				expect(bytecode.BuildArray).toHaveOperand(1),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("concat", 1),
+				expect(bytecode.CallMethod).withOperand(3).toBeSplatCall("log"),
+				expect(bytecode.Pop),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile array splat",
+			Code:     "a = []\nb = [*a, 1, 2, *a]",
+			Matches: []Match{
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.BuildArray).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.Push).withOperand(0).toHaveConstant(1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant(2),
+				expect(bytecode.BuildArray).toHaveOperand(2),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(2).toBeMethodCall("concat", 3),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+		{
+			Scenario: "compile hash splat",
+			Code:     `a = {}` + "\n" + `b = {**a, "k": 1}`,
+			Matches: []Match{
+				expect(bytecode.BuildHash).toHaveOperand(0),
+				expect(bytecode.SetLocal).toHaveOperand(0),
+				expect(bytecode.BuildHash).toHaveOperand(0),
+				expect(bytecode.GetLocal).toHaveOperand(0),
+				expect(bytecode.CallMethod).withOperand(0).toBeMethodCall("merge", 1),
+				expect(bytecode.Push).withOperand(1).toHaveConstant("k"),
+				expect(bytecode.Push).withOperand(2).toHaveConstant(1),
+				expect(bytecode.BuildHash).toHaveOperand(2),
+				expect(bytecode.CallMethod).withOperand(3).toBeMethodCall("merge", 1),
+				expect(bytecode.SetLocal).toHaveOperand(1),
+				expect(bytecode.PushNone),
+				expect(bytecode.Return),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			fun := compile(test.Code)
+
+			instrs := fun.Instrs()
+			if len(instrs) != len(test.Matches) {
+				t.Fatalf("expected %d instructions, got %d", len(test.Matches), len(instrs))
+			}
+
+			for i, instr := range instrs {
+				test.Matches[i].Match(t, instr, fun.Constants())
+			}
+		})
+	}
+}
+
+func TestCompile_Symbols(t *testing.T) {
+	fun := compile("name = \"k\"\nh = {name: 1, :id: 2, (name): 3}")
+
+	matches := []Match{
+		expect(bytecode.Push).withOperand(0).toHaveConstant("k"),
+		expect(bytecode.SetLocal).toHaveOperand(0),
+		expect(bytecode.Push).withOperand(1).toHaveConstant(lang.Intern("name")),
+		expect(bytecode.Push).withOperand(2).toHaveConstant(1),
+		expect(bytecode.Push).withOperand(3).toHaveConstant(lang.Intern("id")),
+		expect(bytecode.Push).withOperand(4).toHaveConstant(2),
+		expect(bytecode.GetLocal).toHaveOperand(0),
+		expect(bytecode.Push).withOperand(5).toHaveConstant(3),
+		expect(bytecode.BuildHash).toHaveOperand(6),
+		expect(bytecode.SetLocal).toHaveOperand(1),
+		expect(bytecode.PushNone),
+		expect(bytecode.Return),
+	}
+
+	instrs := fun.Instrs()
+	if len(instrs) != len(matches) {
+		t.Fatalf("expected %d instructions, got %d", len(matches), len(instrs))
+	}
+
+	for i, instr := range instrs {
+		matches[i].Match(t, instr, fun.Constants())
+	}
+}
+
+func TestCompile_HashComp_Keys(t *testing.T) {
+	fun := compile("pairs = {}\n{(k): v for k, v in pairs}")
+	for _, instr := range fun.Instrs() {
+		if bytecode.Opcode(instr>>8) == bytecode.Push {
+			if _, ok := fun.Constants()[byte(instr&255)].(*lang.Symbol); ok {
+				t.Fatal("expected (k) to use the value of k as the key")
+			}
+		}
+	}
+
+	c := New()
+	_, err := c.Compile(parse("pairs = {}\n{k: v for k, v in pairs}"))
+	expected := "[Lin: 2 Col: 2] the key k would be the symbol :k for every entry, write (k) to use its value"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestCompile_Splat_Errors(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Code     string
+		Expected string
+	}{
+		{
+			Scenario: "rest parameter before the last one",
+			Code:     "fun log(*parts, level String) {}",
+			Expected: "[Lin: 1 Col: 10] rest parameter 'parts' must be the last one",
+		},
+		{
+			Scenario: "rest parameter with default value",
+			Code:     "fun log(*parts Array = []) {}",
+			Expected: "[Lin: 1 Col: 10] rest parameter 'parts' can not have a default value",
+		},
+		{
+			Scenario: "splat outside of calls and literals",
+			Code:     "a = []\nfor x in [[*a for x in a]] {}",
+			Expected: "[Lin: 2 Col: 12] * can only be used in calls, array and hash literals",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			c := New()
+			_, err := c.Compile(parse(test.Code))
+			if err == nil || err.Error() != test.Expected {
+				t.Errorf("expected error %q, got %v", test.Expected, err)
+			}
+		})
+	}
+}
+
 func TestCompile_LogicalOperator_And(t *testing.T) {
 	top := []Match{
 		expect(bytecode.Push).withOperand(0).toHaveConstant(10),
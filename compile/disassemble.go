@@ -29,20 +29,52 @@ func (c *compiler) Disassemble(file *ast.File) {
 				fmt.Printf("%04d ", i)
 				i += 2
 				switch ins.opcode {
-				case bytecode.Push, bytecode.MatchType, bytecode.GetConstant, bytecode.LoadFile, bytecode.DefineField:
+				case
+					bytecode.Push, bytecode.MatchType, bytecode.GetConstant, bytecode.SetConstant,
+					bytecode.GetGlobal, bytecode.SetGlobal, bytecode.LoadFile, bytecode.DefineField,
+					bytecode.DefineEnum:
 					fmt.Fprintf(w, "%-30s%s\n", ins.opcode, fragment.consts[ins.operand])
 				case bytecode.CallMethod, bytecode.CallSuper:
 					ci := fragment.consts[ins.operand].(*lang.CallInfo)
-					fmt.Fprintf(w, "%-30sname: %s argc: %d\n", ins.opcode, ci.Name(), ci.Argc())
+					if ci.Splat() {
+						fmt.Fprintf(w, "%-30sname: %s splat", ins.opcode, ci.Name())
+					} else {
+						fmt.Fprintf(w, "%-30sname: %s argc: %d", ins.opcode, ci.Name(), ci.Argc())
+					}
+
+					if keywords := ci.Keywords(); len(keywords) > 0 {
+						fmt.Fprintf(w, " keywords: %s", strings.Join(keywords, ", "))
+					}
+					if results := ci.Results(); results > 1 {
+						fmt.Fprintf(w, " results: %d", results)
+					}
+					fmt.Fprintln(w)
 				case bytecode.SetLocal, bytecode.GetLocal:
-					fmt.Fprintf(w, "%-30s%s\n", ins.opcode, fragment.locals[ins.operand])
-				case bytecode.JumpIfFalse, bytecode.Jump, bytecode.JumpIfTrue:
+					fmt.Fprintf(w, "%-30s%s\n", ins.opcode, fragment.slotName(ins.operand))
+				case
+					bytecode.JumpIfFalse, bytecode.Jump, bytecode.JumpIfTrue,
+					bytecode.JumpIfTrueOrPop, bytecode.JumpIfFalseOrPop,
+					bytecode.JumpIfNotNoneOrPop, bytecode.JumpIfNone:
 					fmt.Fprintf(w, "%-30s%d\n", ins.opcode, ins.operand*2)
+				case bytecode.JumpTable:
+					table := fragment.consts[ins.operand].(*lang.JumpTable)
+					fmt.Fprintf(w, "%-30sdefault: %d dense: %t\n", ins.opcode, table.Default*2, table.Dense())
+					table.Each(func(key lang.IrObject, target byte) {
+						if _, ok := key.(*lang.String); ok {
+							fmt.Fprintf(w, "%5s%-30q%d\n", "", key, target*2)
+						} else {
+							fmt.Fprintf(w, "%5s%-30s%d\n", "", key, target*2)
+						}
+					})
 				case bytecode.DefineObject, bytecode.DefineFunction:
 					m := fragment.consts[ins.operand].(*lang.Method)
 					fmt.Fprintf(w, "%-30s%s\n", ins.opcode, m.Name())
-				case bytecode.BuildArray, bytecode.BuildHash:
+				case bytecode.BuildArray, bytecode.BuildHash, bytecode.UnpackArray, bytecode.UnpackRest:
 					fmt.Fprintf(w, "%-30ssize: %d\n", ins.opcode, ins.operand)
+				case bytecode.Dup:
+					fmt.Fprintf(w, "%-30scount: %d\n", ins.opcode, ins.operand)
+				case bytecode.BuildRange:
+					fmt.Fprintf(w, "%-30sexclusive: %t\n", ins.opcode, ins.operand == 1)
 				case bytecode.GetField:
 					fmt.Fprintf(w, "%-30s%q\n", ins.opcode, fragment.consts[ins.operand])
 				default:
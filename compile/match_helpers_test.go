@@ -48,6 +48,10 @@ func (m *multiByteMatch) toBeMethodCall(name string, argc byte) *methoCallMatch
 	return &methoCallMatch{multiByteMatch: m, name: name, argc: argc}
 }
 
+func (m *multiByteMatch) toBeSplatCall(name string) *methoCallMatch {
+	return &methoCallMatch{multiByteMatch: m, name: name, argc: 1, splat: true}
+}
+
 func (m *multiByteMatch) Match(t *testing.T, instr uint16, _ []lang.IrObject) {
 	t.Helper()
 
@@ -94,6 +98,8 @@ func (m *constantMatch) Match(t *testing.T, instr uint16, consts []lang.IrObject
 		result = val == string(gotConst.(*lang.String).Value)
 	case bool:
 		result = lang.Bool(val) == gotConst.(lang.Bool)
+	case *lang.Symbol:
+		result = val == gotConst
 	}
 
 	if !result {
@@ -104,8 +110,15 @@ func (m *constantMatch) Match(t *testing.T, instr uint16, consts []lang.IrObject
 type methoCallMatch struct {
 	*multiByteMatch
 
-	name string
-	argc byte
+	name    string
+	argc    byte
+	splat   bool
+	results byte
+}
+
+func (m *methoCallMatch) withResults(results byte) *methoCallMatch {
+	m.results = results
+	return m
 }
 
 func (m *methoCallMatch) Match(t *testing.T, instr uint16, consts []lang.IrObject) {
@@ -130,9 +143,15 @@ func (m *methoCallMatch) Match(t *testing.T, instr uint16, consts []lang.IrObjec
 	if ci.Name() != m.name {
 		t.Errorf("expected name for %s to be %s, got %s", opcode, m.name, ci.Name())
 	}
+	if ci.Splat() != m.splat {
+		t.Errorf("expected splat for %s to be %t, got %t", opcode, m.splat, ci.Splat())
+	}
 	if ci.Argc() != m.argc {
 		t.Errorf("expected argc for %s to be %d, got %d", opcode, m.argc, ci.Argc())
 	}
+	if ci.Results() != m.results {
+		t.Errorf("expected results for %s to be %d, got %d", opcode, m.results, ci.Results())
+	}
 }
 
 type bodyMatch struct {
@@ -24,6 +24,9 @@ type frame struct {
 	stackPointer byte
 	previous     *frame
 	catchOffset  int
+
+	// values the caller destructures from the returned ones
+	results byte
 }
 
 const STACK_SIZE = 1024
@@ -65,8 +68,25 @@ func (f *frame) NewObjectFrame(this lang.IrObject, meth *lang.Method) *frame {
 	}
 }
 
-func (f *frame) NewFrame(this lang.IrObject, argc byte, meth *lang.Method, flags byte) *frame {
-	locals := meth.LocalCount() - meth.Arity()
+func (f *frame) NewFrame(this lang.IrObject, argc byte, meth *lang.Method, flags byte, keywords []lang.IrObject) *frame {
+	if meth.Rest() {
+		argc = f.packRest(argc, meth.Arity())
+	}
+
+	// keyword parameters are the locals right after the positional ones
+	if keywords != nil {
+		for ; argc < meth.Arity(); argc++ {
+			f.Push(lang.None)
+		}
+
+		for _, value := range keywords {
+			f.Push(value)
+			argc++
+		}
+	}
+
+	// every local after the given arguments, missing optional ones included
+	locals := meth.LocalCount() - argc
 	for i := f.stackPointer; i < f.stackPointer+locals; i++ {
 		f.stack[i] = lang.None
 	}
@@ -91,6 +111,21 @@ func (f *frame) NewFrame(this lang.IrObject, argc byte, meth *lang.Method, flags
 	return frame
 }
 
+/*
+Replaces the arguments past the fixed parameters with an Array
+holding them, missing optional arguments are pushed as None so
+the Array lands in the slot of the rest parameter
+*/
+func (f *frame) packRest(argc, arity byte) byte {
+	fixed := arity - 1
+	for ; argc < fixed; argc++ {
+		f.Push(lang.None)
+	}
+
+	f.Push(lang.NewArray(f.PopN(argc - fixed)))
+	return arity
+}
+
 func (f *frame) SetLocal(index byte, value lang.IrObject) {
 	f.stack[index] = value
 }
@@ -115,6 +150,10 @@ func (f *frame) Top(nth byte) lang.IrObject {
 	return f.stack[f.stackPointer-nth-1]
 }
 
+func (f *frame) SetTop(nth byte, object lang.IrObject) {
+	f.stack[f.stackPointer-nth-1] = object
+}
+
 func (f *frame) PeekAt(index byte) lang.IrObject {
 	return f.stack[index]
 }
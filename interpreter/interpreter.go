@@ -20,10 +20,23 @@ type Interpreter struct {
 
 	frameCount int
 	err        *lang.ErrorObject
+
+	// this of the top-level code, its class holds the top-level
+	// functions of every file, the ones loaded with use included
+	script lang.IrObject
+
+	// module-level variables
+	globals map[string]lang.IrObject
+
+	// the constants, objects and enums the program defines
+	namespace *lang.Namespace
 }
 
 func (i *Interpreter) Exec(top *lang.Method) (lang.IrObject, error) {
-	i.PushFrame(lang.NewScript(), 0, top, TOP_FRAME)
+	i.script = lang.NewScript()
+	i.globals = make(map[string]lang.IrObject)
+	i.namespace = lang.NewNamespace()
+	i.PushFrame(i.script, 0, top, TOP_FRAME, nil)
 	return i.dispatch()
 }
 
@@ -77,19 +90,48 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 			i.Pop()
 			goto next_instr
 
+		case bytecode.Dup:
+			for n := byte(0); n < operand; n++ {
+				i.Push(i.Top(operand - 1))
+			}
+
+			goto next_instr
+
 		case bytecode.Return:
-			ret := i.Pop()
+			// a caller destructuring as many values takes them without a tuple
+			if operand > 1 && i.frame.results == operand && i.flags&FLAG_DONE == 0 {
+				i.moveResults(operand)
+				goto resume_frame
+			}
+
+			var values []lang.IrObject
+			var ret lang.IrObject
+			if operand > 1 {
+				values = i.PopN(operand)
+				ret = lang.NewTuple(values)
+			} else {
+				ret = i.Pop()
+			}
+
+			results := i.frame.results
 			if i.PopFrame() {
 				return ret, nil
 			}
 
-			i.Push(ret)
+			if results > 1 {
+				if !i.pushResults(values, ret, results) {
+					goto fail
+				}
+			} else {
+				i.Push(ret)
+			}
+
 			goto resume_frame
 
 		case bytecode.MatchType:
 			err := i.Top(0)
 			name := constants[operand]
-			class := lang.TypeLookup(name)
+			class := i.namespace.LookupType(name)
 			if class == nil {
 				i.err = lang.NewNameError(name)
 				goto fail
@@ -126,12 +168,97 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 
 			goto next_instr
 
+		case bytecode.JumpIfFalseOrPop:
+			if !lang.IsTruthy(i.Top(0)) {
+				i.JumpTo(operand)
+			} else {
+				i.Pop()
+			}
+
+			goto next_instr
+
+		case bytecode.JumpIfTrueOrPop:
+			if lang.IsTruthy(i.Top(0)) {
+				i.JumpTo(operand)
+			} else {
+				i.Pop()
+			}
+
+			goto next_instr
+
+		case bytecode.JumpIfNotNoneOrPop:
+			if i.Top(0) != lang.None {
+				i.JumpTo(operand)
+			} else {
+				i.Pop()
+			}
+
+			goto next_instr
+
+		case bytecode.JumpIfNone:
+			if i.Top(0) == lang.None {
+				i.JumpTo(operand)
+			}
+
+			goto next_instr
+
+		case bytecode.JumpTable:
+			table := constants[operand].(*lang.JumpTable)
+			target, ok := table.Match(i, i.Pop())
+			if !ok {
+				goto fail
+			}
+
+			i.JumpTo(target)
+			goto next_instr
+
 		case bytecode.BuildArray:
 			elements := i.PopN(operand)
 			ary := lang.NewArray(elements)
 			i.Push(ary)
 			goto next_instr
 
+		case bytecode.In:
+			collection := i.Pop()
+			result := lang.Contains(i, i.Pop(), collection)
+			if result == nil {
+				goto fail
+			}
+
+			i.Push(result)
+			goto next_instr
+
+		case bytecode.UnpackArray, bytecode.UnpackRest:
+			unpack := lang.Unpack
+			if opcode == bytecode.UnpackRest {
+				unpack = lang.UnpackRest
+			}
+
+			elements, err := unpack(i.Pop(), int(operand))
+			if err != nil {
+				i.SetError(err)
+				goto fail
+			}
+
+			for n := len(elements) - 1; n >= 0; n-- {
+				i.Push(elements[n])
+			}
+
+			goto next_instr
+
+		case bytecode.BuildRange:
+			end := i.Pop()
+			start := i.Pop()
+
+			r, err := lang.NewRange(start, end, operand == 1)
+			if err != nil {
+				i.SetError(err)
+				goto fail
+			}
+
+			i.Push(r)
+			goto next_instr
+
 		case bytecode.BuildHash:
 			hash := lang.NewHash()
 			hash.BulkInsert(i, i.PopN(operand))
@@ -174,13 +301,37 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 
 		case bytecode.GetConstant:
 			name := constants[operand]
-			class := lang.TypeLookup(name)
-			if class == nil {
+			value := i.namespace.Lookup(name)
+			if value == nil {
 				i.err = lang.NewNameError(name)
 				goto fail
 			}
 
-			i.Push(class)
+			i.Push(value)
+			goto next_instr
+
+		case bytecode.SetConstant:
+			name := lang.GoString(constants[operand])
+			if !i.namespace.DefineConstant(name, i.Pop()) {
+				i.err = lang.NewError("constant %s is already defined", lang.NameError, name)
+				goto fail
+			}
+
+			goto next_instr
+
+		case bytecode.GetGlobal:
+			name := lang.GoString(constants[operand])
+			value, ok := i.globals[name]
+			if !ok {
+				i.err = lang.NewError("undefined global $%s", lang.NameError, name)
+				goto fail
+			}
+
+			i.Push(value)
+			goto next_instr
+
+		case bytecode.SetGlobal:
+			i.globals[lang.GoString(constants[operand])] = i.Pop()
 			goto next_instr
 
 		case bytecode.DefineObject:
@@ -192,10 +343,19 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 			}
 
 			class := lang.NewClass(body.Name(), parent)
-			lang.DefineType(body.Name(), class)
+			i.namespace.DefineType(body.Name(), class)
 			i.PushObjectFrame(class, body)
 			goto start_frame
 
+		case bytecode.DefineEnum:
+			enum := constants[operand].(*lang.Class)
+			if !i.namespace.DefineEnum(enum) {
+				i.err = lang.NewError("constant %s is already defined", lang.NameError, enum.Name())
+				goto fail
+			}
+
+			goto next_instr
+
 		case bytecode.DefineField:
 			class := i.class
 			name := constants[operand]
@@ -209,21 +369,24 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 			goto next_instr
 
 		case bytecode.NewIterator:
-			if it := i.Pop(); it.Is(lang.ArrayClass) {
-				iter := lang.NewIterator(it)
-				i.Push(iter)
-				goto next_instr
+			iter := lang.NewIterator(i, i.Pop())
+			if iter == nil {
+				goto fail
 			}
 
-			err := lang.NewTypeError("object is not iterable")
-			i.SetError(err)
-			goto fail
+			i.Push(iter)
+			goto next_instr
 
 		case bytecode.Iterate:
 			iter := i.Top(0).(*lang.Iterator)
 
-			if iter.HasNext() {
-				i.Push(iter.Next())
+			item := iter.Next(i)
+			if item == nil {
+				goto fail
+			}
+
+			if item != lang.StopIteration {
+				i.Push(item)
 				i.Push(lang.True)
 			} else {
 				i.Pop()
@@ -234,16 +397,29 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 
 		case bytecode.CallMethod:
 			info := constants[operand].(*lang.CallInfo)
-			recv := i.Top(info.Argc())
+			argc, keywords, ok := i.spreadArgs(info)
+			if !ok {
+				goto fail
+			}
+
+			recv := i.Top(argc)
 			class := recv.Class()
 			method := class.LookupMethod(info.Name())
 
+			// top-level functions are callable from methods
+			if method == nil && info.Implicit() && i.script != nil {
+				if method = i.script.Class().LookupMethod(info.Name()); method != nil {
+					recv = i.script
+					i.SetTop(argc, recv)
+				}
+			}
+
 			if method == nil {
 				i.err = lang.NewNoMethodError(recv, info.Name())
 				goto fail
 			}
 
-			switch i.call0(recv, method, info) {
+			switch i.call0(recv, method, argc, info, keywords) {
 			case CALL_OK:
 				goto next_instr
 			case CALL_NEW_FRAME:
@@ -259,7 +435,12 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 			}
 
 			info := constants[operand].(*lang.CallInfo)
-			recv := i.Top(info.Argc())
+			argc, keywords, ok := i.spreadArgs(info)
+			if !ok {
+				goto fail
+			}
+
+			recv := i.Top(argc)
 			super := recv.Class().Super()
 			method := super.LookupMethod(info.Name())
 
@@ -273,7 +454,7 @@ func (i *Interpreter) dispatch() (lang.IrObject, error) {
 				goto fail
 			}
 
-			switch i.call0(recv, method, info) {
+			switch i.call0(recv, method, argc, info, keywords) {
 			case CALL_OK:
 				goto next_instr
 			case CALL_NEW_FRAME:
@@ -316,11 +497,11 @@ func (i *Interpreter) PushObjectFrame(this lang.IrObject, fun *lang.Method) {
 	i.frameCount++
 }
 
-func (i *Interpreter) PushFrame(this lang.IrObject, argc byte, fun *lang.Method, flags byte) {
+func (i *Interpreter) PushFrame(this lang.IrObject, argc byte, fun *lang.Method, flags byte, keywords []lang.IrObject) {
 	if i.frame == nil {
 		i.frame = TopFrame(this, fun)
 	} else {
-		i.frame = i.NewFrame(this, argc, fun, flags)
+		i.frame = i.NewFrame(this, argc, fun, flags, keywords)
 	}
 
 	i.frameCount++
@@ -341,10 +522,10 @@ func (i *Interpreter) SetError(err *lang.ErrorObject) {
 	i.err = err
 }
 
-func (i *Interpreter) callGoFunc(recv lang.IrObject, method lang.Native, argc byte) int {
+func (i *Interpreter) callGoFunc(recv lang.IrObject, method lang.Native, argc byte, names []string, keywords []lang.IrObject) int {
 	args := i.PopN(argc + 1) // +1 recv
 
-	if val := method.Invoke(i, recv, args[1:]...); val != nil {
+	if val := lang.InvokeNative(i, method, recv, names, keywords, args[1:]...); val != nil {
 		i.Push(val)
 		return CALL_OK
 	}
@@ -352,18 +533,107 @@ func (i *Interpreter) callGoFunc(recv lang.IrObject, method lang.Native, argc by
 	return CALL_ERROR
 }
 
-func (i *Interpreter) call0(recv lang.IrObject, method *lang.Method, info *lang.CallInfo) int {
+/*
+Pops the values of the keyword arguments and replaces the array
+of a splat call with its elements, returning the number of
+positional arguments left on the stack
+*/
+func (i *Interpreter) spreadArgs(info *lang.CallInfo) (byte, []lang.IrObject, bool) {
+	var keywords []lang.IrObject
+	if count := byte(len(info.Keywords())); count > 0 {
+		keywords = i.PopN(count)
+	}
+
+	if !info.Splat() {
+		return info.Argc() - byte(len(keywords)), keywords, true
+	}
+
+	args, err := lang.Spread(i.Pop())
+	if err != nil {
+		i.err = err
+		return 0, nil, false
+	}
+
+	for _, arg := range args {
+		i.Push(arg)
+	}
+
+	return byte(len(args)), keywords, true
+}
+
+/*
+Pushes the values a caller destructures in the order
+UNPACK_ARRAY leaves them. The values of a multiple return
+are pushed as they are, anything else is unpacked
+*/
+func (i *Interpreter) pushResults(values []lang.IrObject, ret lang.IrObject, count byte) bool {
+	if len(values) != int(count) {
+		var err *lang.ErrorObject
+		if values, err = lang.Unpack(ret, int(count)); err != nil {
+			i.SetError(err)
+			return false
+		}
+	}
+
+	for n := len(values) - 1; n >= 0; n-- {
+		i.Push(values[n])
+	}
+
+	return true
+}
+
+/*
+Pops the frame moving its count values straight into the
+stack of the caller, in the order UNPACK_ARRAY leaves them
+*/
+func (i *Interpreter) moveResults(count byte) {
+	callee := i.frame
+	top := callee.stackPointer
+	values := callee.stack[top-count : top]
+	for l, r := 0, len(values)-1; l < r; l, r = l+1, r-1 {
+		values[l], values[r] = values[r], values[l]
+	}
+
+	callee.stackPointer -= count
+	i.PopFrame()
+
+	// the callee stack starts inside the caller one, below the values
+	caller := i.frame
+	end := cap(caller.stack) - cap(callee.stack) + int(top)
+	copy(caller.stack[caller.stackPointer:], values)
+	caller.stackPointer += count
+
+	for n := int(caller.stackPointer); n < end; n++ {
+		caller.stack[n] = nil
+	}
+}
+
+func (i *Interpreter) call0(recv lang.IrObject, method *lang.Method, argc byte, info *lang.CallInfo, keywords []lang.IrObject) int {
+	names := info.Keywords()
+
 	switch method.MethodType() {
 	case lang.GoFunction:
-		return i.callGoFunc(recv, method.Native(), info.Argc())
+		status := i.callGoFunc(recv, method.Native(), argc, names, keywords)
+		if status == CALL_OK && info.Results() > 1 && !i.pushResults(nil, i.Pop(), info.Results()) {
+			return CALL_ERROR
+		}
+
+		return status
 
 	case lang.IrMethod:
-		if err := method.CheckArity(info.Argc()); err != nil {
+		if err := method.CheckArity(argc); err != nil {
+			i.err = err
+			return CALL_ERROR
+		}
+
+		bound, err := method.BindKeywords(names, keywords)
+		if err != nil {
 			i.err = err
 			return CALL_ERROR
 		}
 
-		i.PushFrame(recv, info.Argc(), method, IRMETHOD_FRAME)
+		i.PushFrame(recv, argc, method, IRMETHOD_FRAME, bound)
+		i.frame.results = info.Results()
 		return CALL_NEW_FRAME
 	default:
 		lang.Unreachable()
@@ -372,20 +642,34 @@ func (i *Interpreter) call0(recv lang.IrObject, method *lang.Method, info *lang.
 }
 
 func (i *Interpreter) Call(recv lang.IrObject, method *lang.Method, args ...lang.IrObject) lang.IrObject {
+	return i.CallKeywords(recv, method, nil, nil, args...)
+}
+
+/*
+Calls an Iracema method from Go, binding names
+and keywords to its keyword parameters
+*/
+func (i *Interpreter) CallKeywords(recv lang.IrObject, method *lang.Method, names []string, keywords []lang.IrObject, args ...lang.IrObject) lang.IrObject {
 	argc := len(args)
 
 	if i.err = method.CheckArity(byte(argc)); i.err != nil {
 		return nil
 	}
 
+	bound, err := method.BindKeywords(names, keywords)
+	if err != nil {
+		i.err = err
+		return nil
+	}
+
 	i.Push(recv)
 	for _, arg := range args {
 		i.Push(arg)
 	}
 
-	i.PushFrame(recv, byte(len(args)), method, FLAG_DONE|IRMETHOD_FRAME)
-	ret, err := i.dispatch()
-	if err != nil {
+	i.PushFrame(recv, byte(len(args)), method, FLAG_DONE|IRMETHOD_FRAME, bound)
+	ret, dispatchErr := i.dispatch()
+	if dispatchErr != nil {
 		i.err = lang.NewError("unknown error:", lang.Error)
 		return nil
 	}
@@ -415,6 +699,11 @@ func (i *Interpreter) loadFIle(name lang.IrObject) int {
 		return CALL_ERROR
 	}
 
-	i.PushFrame(i.this, 0, method, TOP_FRAME)
+	// reported like the warnings of the file being run
+	for _, warning := range c.Warnings() {
+		fmt.Fprintf(os.Stderr, "\x1b[0;33m%s\x1b[0;0m\n", warning)
+	}
+
+	i.PushFrame(i.this, 0, method, TOP_FRAME, nil)
 	return CALL_NEW_FRAME
 }
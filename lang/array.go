@@ -2,9 +2,27 @@ package lang
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
+/*
+Returns the values held by an Array or a Tuple, for
+the places where both can be destructured
+*/
+func toElements(value IrObject) ([]IrObject, *ErrorObject) {
+	if tuple, ok := value.(*Tuple); ok {
+		return tuple.Elements, nil
+	}
+
+	array, err := toArray(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return array.Elements, nil
+}
+
 func toArray(value IrObject) (*Array, *ErrorObject) {
 	if array, ok := value.(*Array); ok {
 		return array, nil
@@ -27,6 +45,62 @@ func checkBoundaries(index int, size int) (int, *ErrorObject) {
 	return index, nil
 }
 
+/*
+Returns the elements of value to be assigned
+to the given number of variables
+*/
+func Unpack(value IrObject, count int) ([]IrObject, *ErrorObject) {
+	elements, err := toElements(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) != count {
+		return nil, NewError("wrong number of values to unpack (given %d, expected %d)", ArgumentError, len(elements), count)
+	}
+
+	return elements, nil
+}
+
+/*
+Returns the elements of value to be passed as arguments
+*/
+func Spread(value IrObject) ([]IrObject, *ErrorObject) {
+	elements, err := toElements(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) > 255 {
+		return nil, NewError("too many arguments to spread (given %d, expected at most 255)", ArgumentError, len(elements))
+	}
+
+	return elements, nil
+}
+
+/*
+Returns the first count elements of value followed by an
+array holding the remaining ones
+*/
+func UnpackRest(value IrObject, count int) ([]IrObject, *ErrorObject) {
+	values, err := toElements(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) < count {
+		return nil, NewError("wrong number of values to unpack (given %d, expected at least %d)", ArgumentError, len(values), count)
+	}
+
+	elements := make([]IrObject, count, count+1)
+	copy(elements, values)
+
+	rest := make([]IrObject, len(values)-count)
+	copy(rest, values[count:])
+
+	return append(elements, NewArray(rest)), nil
+}
+
 func ARRAY(obj IrObject) *Array {
 	return obj.(*Array)
 }
@@ -58,10 +132,19 @@ func arrayInspect(rt Runtime, this IrObject) IrObject {
 	return NewString(buf.String())
 }
 
+/*
+Inserts element before the given index, shifting the following
+elements. Negative indices count from the end of the array
+*/
 func arrayInsert(rt Runtime, this, index, element IrObject) IrObject {
 	array := ARRAY(this)
 
-	idx := INT(index)
+	idx, err := toInt(index)
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
 	size := len(array.Elements)
 
 	pos, err := checkBoundaries(int(idx), size)
@@ -74,13 +157,53 @@ func arrayInsert(rt Runtime, this, index, element IrObject) IrObject {
 	return element
 }
 
+func arraySet(rt Runtime, this, index, element IrObject) IrObject {
+	array := ARRAY(this)
+
+	idx, err := toInt(index)
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
+	pos, err := checkBoundaries(int(idx), len(array.Elements))
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
+	array.Elements[pos] = element
+	return element
+}
+
+func arraySlice(array *Array, r *Range) IrObject {
+	start, end, ok := rangeBounds(r, len(array.Elements))
+	if !ok {
+		return None
+	}
+
+	var elements []IrObject
+	eachIndex(r, start, end, func(i int) {
+		elements = append(elements, array.Elements[i])
+	})
+
+	return NewArray(elements)
+}
+
 func arrayAt(rt Runtime, this IrObject, index IrObject) IrObject {
 	array := ARRAY(this)
 
-	idx := INT(index)
-	size := len(array.Elements)
+	if r, ok := index.(*Range); ok {
+		return arraySlice(array, r)
+	}
 
-	pos, err := checkBoundaries(int(idx), size)
+	idx, err := toInt(index)
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
+	pos, err := checkBoundaries(int(idx), len(array.Elements))
 	if err != nil {
 		rt.SetError(err)
 		return nil
@@ -135,7 +258,19 @@ func arrayValuesAt(rt Runtime, this IrObject, indices ...IrObject) IrObject {
 	elements := make([]IrObject, len(indices))
 
 	for i, index := range indices {
-		elements[i] = array.Elements[INT(index)]
+		idx, err := toInt(index)
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		pos, err := checkBoundaries(int(idx), len(array.Elements))
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		elements[i] = array.Elements[pos]
 	}
 
 	return NewArray(elements)
@@ -146,15 +281,17 @@ func arrayHash(rt Runtime, this IrObject) IrObject {
 
 	hash := Int(1)
 	for _, el := range array.Elements {
-		if code := call(rt, el, "hash"); code != nil {
-			hash = hash*31 + INT(code)
+		code, ok := callHash(rt, el)
+		if !ok {
+			return nil
 		}
 
-		return nil
+		hash = hash*31 + code
 	}
 
 	return hash
 }
+
 func arrayUniq(rt Runtime, this IrObject) IrObject {
 	ary := ARRAY(this)
 	if len(ary.Elements) <= 1 {
@@ -211,10 +348,9 @@ func arrayEqual(rt Runtime, this IrObject, other IrObject) IrObject {
 		return True
 	}
 
-	y, err := toArray(other)
-	if err != nil {
-		rt.SetError(err)
-		return nil
+	y, ok := other.(*Array)
+	if !ok {
+		return False
 	}
 
 	x := ARRAY(this)
@@ -276,6 +412,493 @@ func arrayMinus(rt Runtime, this, other IrObject) IrObject {
 	return NewArray(result)
 }
 
+func arrayPop(rt Runtime, this IrObject) IrObject {
+	array := ARRAY(this)
+
+	size := len(array.Elements)
+	if size == 0 {
+		return None
+	}
+
+	last := array.Elements[size-1]
+	array.Elements = array.Elements[:size-1]
+	return last
+}
+
+func arrayUnshift(rt Runtime, this IrObject, elements ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	result := make([]IrObject, 0, len(elements)+len(array.Elements))
+	result = append(result, elements...)
+	array.Elements = append(result, array.Elements...)
+	return array
+}
+
+func arrayDeleteAt(rt Runtime, this, index IrObject) IrObject {
+	array := ARRAY(this)
+
+	idx, err := toInt(index)
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
+	pos, err := checkBoundaries(int(idx), len(array.Elements))
+	if err != nil {
+		return None
+	}
+
+	element := array.Elements[pos]
+	array.Elements = append(array.Elements[:pos], array.Elements[pos+1:]...)
+	return element
+}
+
+/*
+Returns the position of the first element equal to value,
+-1 when there is none and -2 when calling == failed
+*/
+func indexOf(rt Runtime, elements []IrObject, value IrObject) int {
+	for i, el := range elements {
+		equal := call(rt, el, "==", value)
+		if equal == nil {
+			return -2
+		}
+
+		if IsTruthy(equal) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+/*
+Removes every element equal to value, returning
+value or None when nothing was removed
+*/
+func arrayDelete(rt Runtime, this, value IrObject) IrObject {
+	array := ARRAY(this)
+
+	var kept []IrObject
+	for _, el := range array.Elements {
+		equal := call(rt, el, "==", value)
+		if equal == nil {
+			return nil
+		}
+
+		if !IsTruthy(equal) {
+			kept = append(kept, el)
+		}
+	}
+
+	if len(kept) == len(array.Elements) {
+		return None
+	}
+
+	array.Elements = kept
+	return value
+}
+
+func arrayIndex(rt Runtime, this, value IrObject) IrObject {
+	switch idx := indexOf(rt, ARRAY(this).Elements, value); idx {
+	case -2:
+		return nil
+	case -1:
+		return None
+	default:
+		return Int(idx)
+	}
+}
+
+func arrayInclude(rt Runtime, this, value IrObject) IrObject {
+	idx := indexOf(rt, ARRAY(this).Elements, value)
+	if idx == -2 {
+		return nil
+	}
+
+	return Bool(idx >= 0)
+}
+
+func arrayCount(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	switch len(args) {
+	case 0:
+		return Int(len(array.Elements))
+	case 1:
+		var count Int
+		for _, el := range array.Elements {
+			equal := call(rt, el, "==", args[0])
+			if equal == nil {
+				return nil
+			}
+
+			if IsTruthy(equal) {
+				count++
+			}
+		}
+
+		return count
+	default:
+		rt.SetError(NewArityError(len(args), 1))
+		return nil
+	}
+}
+
+func arrayEmpty(rt Runtime, this IrObject) IrObject {
+	return Bool(len(ARRAY(this).Elements) == 0)
+}
+
+func toCount(rt Runtime, value IrObject) (int, bool) {
+	n, err := toInt(value)
+	if err != nil {
+		rt.SetError(err)
+		return 0, false
+	}
+
+	if n < 0 {
+		rt.SetError(NewError("negative array size", ArgumentError))
+		return 0, false
+	}
+
+	return int(n), true
+}
+
+/*
+Returns a new array with the elements in [start, end),
+both already within the array bounds
+*/
+func subArray(array *Array, start, end int) *Array {
+	elements := make([]IrObject, end-start)
+	copy(elements, array.Elements[start:end])
+	return NewArray(elements)
+}
+
+func arrayTake(rt Runtime, this, size IrObject) IrObject {
+	array := ARRAY(this)
+
+	n, ok := toCount(rt, size)
+	if !ok {
+		return nil
+	}
+
+	if n > len(array.Elements) {
+		n = len(array.Elements)
+	}
+
+	return subArray(array, 0, n)
+}
+
+func arrayDrop(rt Runtime, this, size IrObject) IrObject {
+	array := ARRAY(this)
+
+	n, ok := toCount(rt, size)
+	if !ok {
+		return nil
+	}
+
+	if n > len(array.Elements) {
+		n = len(array.Elements)
+	}
+
+	return subArray(array, n, len(array.Elements))
+}
+
+/*
+Returns the first element, or an array with
+the first n elements when n is given
+*/
+func arrayFirst(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	switch len(args) {
+	case 0:
+		if len(array.Elements) == 0 {
+			return None
+		}
+
+		return array.Elements[0]
+	case 1:
+		return arrayTake(rt, this, args[0])
+	default:
+		rt.SetError(NewArityError(len(args), 1))
+		return nil
+	}
+}
+
+/*
+Returns the last element, or an array with
+the last n elements when n is given
+*/
+func arrayLast(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	array := ARRAY(this)
+	size := len(array.Elements)
+
+	switch len(args) {
+	case 0:
+		if size == 0 {
+			return None
+		}
+
+		return array.Elements[size-1]
+	case 1:
+		n, ok := toCount(rt, args[0])
+		if !ok {
+			return nil
+		}
+
+		if n > size {
+			n = size
+		}
+
+		return subArray(array, size-n, size)
+	default:
+		rt.SetError(NewArityError(len(args), 1))
+		return nil
+	}
+}
+
+/*
+Returns the elements within a range, or length elements from start
+when called with two arguments. Negative starts count from the end
+*/
+func arraySliceOf(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	switch len(args) {
+	case 1:
+		if r, ok := args[0].(*Range); ok {
+			return arraySlice(array, r)
+		}
+
+		return arrayAt(rt, this, args[0])
+	case 2:
+		start, err := toInt(args[0])
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		length, err := toInt(args[1])
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		size := len(array.Elements)
+		if length < 0 {
+			return None
+		}
+
+		// the start may also be right after the last element
+		pos := size
+		if int(start) != size {
+			if pos, err = checkBoundaries(int(start), size); err != nil {
+				return None
+			}
+		}
+
+		// clamped before adding so the end cannot overflow
+		if length > Int(size-pos) {
+			length = Int(size - pos)
+		}
+
+		return subArray(array, pos, pos+int(length))
+	default:
+		rt.SetError(NewArityError(len(args), 2))
+		return nil
+	}
+}
+
+func arrayJoin(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	var separator []byte
+	switch len(args) {
+	case 0:
+	case 1:
+		str, ok := args[0].(*String)
+		if !ok {
+			rt.SetError(NewTypeError("no implicit conversion of %s into String", args[0].Class()))
+			return nil
+		}
+
+		separator = str.Value
+	default:
+		rt.SetError(NewArityError(len(args), 1))
+		return nil
+	}
+
+	var buf strings.Builder
+	for i, el := range array.Elements {
+		if i > 0 {
+			buf.Write(separator)
+		}
+
+		str := call(rt, el, "to_str")
+		if str == nil {
+			return nil
+		}
+
+		buf.Write(unwrapString(str))
+	}
+
+	return NewString(buf.String())
+}
+
+/*
+Compares elements with <, failing unless it returns a Boolean
+*/
+func lessThan(rt Runtime, x, y IrObject) (bool, bool) {
+	less := call(rt, x, "<", y)
+	if less == nil {
+		return false, false
+	}
+
+	result, ok := less.(Bool)
+	if !ok {
+		rt.SetError(NewTypeError("comparison of %s with %s failed", x.Class(), y.Class()))
+		return false, false
+	}
+
+	return bool(result), true
+}
+
+func arraySort(rt Runtime, this IrObject) IrObject {
+	array := ARRAY(this)
+
+	elements := make([]IrObject, len(array.Elements))
+	copy(elements, array.Elements)
+
+	failed := false
+	sort.SliceStable(elements, func(i, j int) bool {
+		if failed {
+			return false
+		}
+
+		less, ok := lessThan(rt, elements[i], elements[j])
+		failed = !ok
+		return less
+	})
+
+	if failed {
+		return nil
+	}
+
+	return NewArray(elements)
+}
+
+/*
+Returns the element for which better(candidate, current) holds
+against every other element, None for empty arrays
+*/
+func arrayExtreme(rt Runtime, array *Array, better func(x, y IrObject) (bool, bool)) IrObject {
+	if len(array.Elements) == 0 {
+		return None
+	}
+
+	result := array.Elements[0]
+	for _, el := range array.Elements[1:] {
+		ok, valid := better(el, result)
+		if !valid {
+			return nil
+		}
+
+		if ok {
+			result = el
+		}
+	}
+
+	return result
+}
+
+func arrayMin(rt Runtime, this IrObject) IrObject {
+	return arrayExtreme(rt, ARRAY(this), func(x, y IrObject) (bool, bool) {
+		return lessThan(rt, x, y)
+	})
+}
+
+func arrayMax(rt Runtime, this IrObject) IrObject {
+	return arrayExtreme(rt, ARRAY(this), func(x, y IrObject) (bool, bool) {
+		return lessThan(rt, y, x)
+	})
+}
+
+func arraySum(rt Runtime, this IrObject) IrObject {
+	var sum IrObject = Int(0)
+	for _, el := range ARRAY(this).Elements {
+		if sum = call(rt, sum, "+", el); sum == nil {
+			return nil
+		}
+	}
+
+	return sum
+}
+
+func arrayConcat(rt Runtime, this IrObject, others ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	for _, other := range others {
+		y, err := toArray(other)
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		array.Elements = append(array.Elements, y.Elements...)
+	}
+
+	return array
+}
+
+/*
+Merges elements at the same position into arrays, missing
+elements in shorter arrays are filled with None
+*/
+func arrayZip(rt Runtime, this IrObject, others ...IrObject) IrObject {
+	array := ARRAY(this)
+
+	arrays := make([]*Array, len(others))
+	for i, other := range others {
+		y, err := toArray(other)
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		arrays[i] = y
+	}
+
+	result := make([]IrObject, len(array.Elements))
+	for i, el := range array.Elements {
+		tuple := make([]IrObject, 0, len(arrays)+1)
+		tuple = append(tuple, el)
+
+		for _, y := range arrays {
+			if i < len(y.Elements) {
+				tuple = append(tuple, y.Elements[i])
+			} else {
+				tuple = append(tuple, None)
+			}
+		}
+
+		result[i] = NewArray(tuple)
+	}
+
+	return NewArray(result)
+}
+
+func arrayCompact(rt Runtime, this IrObject) IrObject {
+	var elements []IrObject
+	for _, el := range ARRAY(this).Elements {
+		if el != None {
+			elements = append(elements, el)
+		}
+	}
+
+	return NewArray(elements)
+}
+
 var ArrayClass *Class
 
 func InitArray() {
@@ -301,6 +924,28 @@ func InitArray() {
 	ArrayClass.AddGoMethod("flatten", zeroArgs(arrayFlatten))
 	ArrayClass.AddGoMethod("uniq", zeroArgs(arrayUniq))
 	ArrayClass.AddGoMethod("shift", oneArg(arrayShift))
+	ArrayClass.AddGoMethod("set", twoArgs(arraySet))
+	ArrayClass.AddGoMethod("pop", zeroArgs(arrayPop))
+	ArrayClass.AddGoMethod("unshift", nArgs(arrayUnshift))
+	ArrayClass.AddGoMethod("delete_at", oneArg(arrayDeleteAt))
+	ArrayClass.AddGoMethod("delete", oneArg(arrayDelete))
+	ArrayClass.AddGoMethod("index", oneArg(arrayIndex))
+	ArrayClass.AddGoMethod("include?", oneArg(arrayInclude))
+	ArrayClass.AddGoMethod("first", nArgs(arrayFirst))
+	ArrayClass.AddGoMethod("last", nArgs(arrayLast))
+	ArrayClass.AddGoMethod("slice", nArgs(arraySliceOf))
+	ArrayClass.AddGoMethod("join", nArgs(arrayJoin))
+	ArrayClass.AddGoMethod("sort", zeroArgs(arraySort))
+	ArrayClass.AddGoMethod("min", zeroArgs(arrayMin))
+	ArrayClass.AddGoMethod("max", zeroArgs(arrayMax))
+	ArrayClass.AddGoMethod("sum", zeroArgs(arraySum))
+	ArrayClass.AddGoMethod("count", nArgs(arrayCount))
+	ArrayClass.AddGoMethod("empty?", zeroArgs(arrayEmpty))
+	ArrayClass.AddGoMethod("concat", nArgs(arrayConcat))
+	ArrayClass.AddGoMethod("zip", nArgs(arrayZip))
+	ArrayClass.AddGoMethod("take", oneArg(arrayTake))
+	ArrayClass.AddGoMethod("drop", oneArg(arrayDrop))
+	ArrayClass.AddGoMethod("compact", zeroArgs(arrayCompact))
 }
 
 type Array struct {
@@ -0,0 +1,205 @@
+package lang
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func ints(values ...Int) *Array {
+	elements := make([]IrObject, len(values))
+	for i, value := range values {
+		elements[i] = value
+	}
+
+	return NewArray(elements)
+}
+
+func assertArray(t *testing.T, got IrObject, expected *Array) {
+	t.Helper()
+
+	if arrayEqual(globalTestDummyRuntime, got, expected) != True {
+		t.Errorf("expected array to be %s, got %s", arrayInspect(globalTestDummyRuntime, expected), arrayInspect(globalTestDummyRuntime, got))
+	}
+}
+
+func Test_arraySet(t *testing.T) {
+	array := ints(1, 2, 3)
+
+	arraySet(globalTestDummyRuntime, array, Int(0), Int(10))
+	arraySet(globalTestDummyRuntime, array, Int(-1), Int(30))
+	assertArray(t, array, ints(10, 2, 30))
+
+	rt := new(dummyRuntime)
+	if result := arraySet(rt, array, Int(3), Int(1)); result != nil || rt.err == nil {
+		t.Errorf("expected out of bounds error, got %v", result)
+	}
+}
+
+func Test_arrayAt(t *testing.T) {
+	array := ints(1, 2, 3)
+
+	assertEqual(t, arrayAt(globalTestDummyRuntime, array, Int(-1)), Int(3))
+
+	huge := NewInteger(new(big.Int).Lsh(big.NewInt(1), 64))
+	for _, index := range []IrObject{huge, NewString("0"), Int(3)} {
+		rt := new(dummyRuntime)
+		if result := arrayAt(rt, array, index); result != nil || rt.err == nil {
+			t.Errorf("expected an error for index %v, got %v", index, result)
+		}
+
+		rt = new(dummyRuntime)
+		if result := arrayValuesAt(rt, array, Int(0), index); result != nil || rt.err == nil {
+			t.Errorf("expected values_at error for index %v, got %v", index, result)
+		}
+	}
+}
+
+func Test_arrayInsert(t *testing.T) {
+	array := ints(1, 2, 3)
+
+	arrayInsert(globalTestDummyRuntime, array, Int(1), Int(20))
+	arrayInsert(globalTestDummyRuntime, array, Int(-1), Int(30))
+	assertArray(t, array, ints(1, 20, 30))
+}
+
+func Test_arrayPopAndUnshift(t *testing.T) {
+	array := ints(2, 3)
+
+	assertEqual(t, arrayPop(globalTestDummyRuntime, array), Int(3))
+	arrayUnshift(globalTestDummyRuntime, array, Int(0), Int(1))
+	assertArray(t, array, ints(0, 1, 2))
+
+	if value := arrayPop(globalTestDummyRuntime, ints()); value != None {
+		t.Errorf("expected None, got %v", value)
+	}
+}
+
+func Test_arrayDelete(t *testing.T) {
+	array := ints(1, 2, 1, 3)
+
+	assertEqual(t, arrayDelete(globalTestDummyRuntime, array, Int(1)), Int(1))
+	assertArray(t, array, ints(2, 3))
+
+	assertEqual(t, arrayDeleteAt(globalTestDummyRuntime, array, Int(-1)), Int(3))
+	assertArray(t, array, ints(2))
+
+	if value := arrayDeleteAt(globalTestDummyRuntime, array, Int(5)); value != None {
+		t.Errorf("expected None, got %v", value)
+	}
+}
+
+func Test_arraySearch(t *testing.T) {
+	array := ints(5, 6, 6)
+
+	assertEqual(t, arrayIndex(globalTestDummyRuntime, array, Int(6)), Int(1))
+	assertEqual(t, arrayInclude(globalTestDummyRuntime, array, Int(7)), False)
+	assertEqual(t, arrayCount(globalTestDummyRuntime, array, Int(6)), Int(2))
+	assertEqual(t, arrayCount(globalTestDummyRuntime, array), Int(3))
+
+	if value := arrayIndex(globalTestDummyRuntime, array, Int(7)); value != None {
+		t.Errorf("expected None, got %v", value)
+	}
+}
+
+func Test_arraySubsets(t *testing.T) {
+	array := ints(1, 2, 3, 4, 5)
+
+	tests := []struct {
+		Scenario string
+		Result   IrObject
+		Expected *Array
+	}{
+		{Scenario: "first n", Result: arrayFirst(globalTestDummyRuntime, array, Int(2)), Expected: ints(1, 2)},
+		{Scenario: "last n", Result: arrayLast(globalTestDummyRuntime, array, Int(2)), Expected: ints(4, 5)},
+		{Scenario: "take", Result: arrayTake(globalTestDummyRuntime, array, Int(10)), Expected: array},
+		{Scenario: "drop", Result: arrayDrop(globalTestDummyRuntime, array, Int(3)), Expected: ints(4, 5)},
+		{Scenario: "slice", Result: arraySliceOf(globalTestDummyRuntime, array, Int(1), Int(2)), Expected: ints(2, 3)},
+		{Scenario: "slice negative", Result: arraySliceOf(globalTestDummyRuntime, array, Int(-2), Int(5)), Expected: ints(4, 5)},
+		{Scenario: "slice past Int", Result: arraySliceOf(globalTestDummyRuntime, array, Int(1), Int(math.MaxInt64)), Expected: ints(2, 3, 4, 5)},
+		{Scenario: "slice at the end", Result: arraySliceOf(globalTestDummyRuntime, array, Int(5), Int(1)), Expected: ints()},
+		{Scenario: "slice range", Result: arraySliceOf(globalTestDummyRuntime, array, newTestRange(t, 0, -4, false)), Expected: ints(1, 2)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			assertArray(t, test.Result, test.Expected)
+		})
+	}
+
+	assertEqual(t, arrayFirst(globalTestDummyRuntime, array), Int(1))
+	assertEqual(t, arrayLast(globalTestDummyRuntime, array), Int(5))
+	assertEqual(t, arraySliceOf(globalTestDummyRuntime, array, Int(-1)), Int(5))
+}
+
+func Test_arrayJoin(t *testing.T) {
+	array := NewArray([]IrObject{Int(1), NewString("a"), Float(1.5)})
+
+	assertEqual(t, arrayJoin(globalTestDummyRuntime, array, NewString(", ")), NewString("1, a, 1.500000"))
+	assertEqual(t, arrayJoin(globalTestDummyRuntime, ints(1, 2)), NewString("12"))
+}
+
+func Test_arraySort(t *testing.T) {
+	array := ints(3, 1, 2)
+
+	assertArray(t, arraySort(globalTestDummyRuntime, array), ints(1, 2, 3))
+	assertArray(t, array, ints(3, 1, 2))
+	assertEqual(t, arrayMin(globalTestDummyRuntime, array), Int(1))
+	assertEqual(t, arrayMax(globalTestDummyRuntime, array), Int(3))
+	assertEqual(t, arraySum(globalTestDummyRuntime, array), Int(6))
+
+	rt := new(dummyRuntime)
+	mixed := NewArray([]IrObject{NewString("a"), Int(1)})
+	if result := arraySort(rt, mixed); result != nil || rt.err == nil {
+		t.Errorf("expected comparison error, got %v", result)
+	}
+}
+
+func Test_arrayCombinations(t *testing.T) {
+	array := ints(1, 2)
+
+	zipped := arrayZip(globalTestDummyRuntime, array, ints(3))
+	assertArray(t, zipped, NewArray([]IrObject{ints(1, 3), NewArray([]IrObject{Int(2), None})}))
+
+	compact := arrayCompact(globalTestDummyRuntime, NewArray([]IrObject{None, Int(1), None}))
+	assertArray(t, compact, ints(1))
+
+	arrayConcat(globalTestDummyRuntime, array, ints(3), ints(4))
+	assertArray(t, array, ints(1, 2, 3, 4))
+	assertEqual(t, arrayEmpty(globalTestDummyRuntime, array), False)
+}
+
+func Test_UnpackRest(t *testing.T) {
+	array := NewArray([]IrObject{Int(1), Int(2), Int(3)})
+
+	elements, err := UnpackRest(array, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.message)
+	}
+
+	assertEqual(t, elements[0], Int(1))
+	rest := ARRAY(elements[1]).Elements
+	if len(rest) != 2 {
+		t.Fatalf("expected rest to have 2 elements, got %d", len(rest))
+	}
+
+	assertEqual(t, rest[0], Int(2))
+	assertEqual(t, rest[1], Int(3))
+
+	if _, err := UnpackRest(array, 4); err == nil || err.message != "wrong number of values to unpack (given 3, expected at least 4)" {
+		t.Errorf("expected unpack error, got %v", err)
+	}
+}
+
+func Test_Spread(t *testing.T) {
+	args, err := Spread(ints(1, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.message)
+	}
+
+	assertArray(t, NewArray(args), ints(1, 2))
+
+	if _, err := Spread(Int(1)); err == nil {
+		t.Error("expected spreading an Int to fail")
+	}
+}
@@ -0,0 +1,67 @@
+package lang
+
+import (
+	"math/big"
+)
+
+func BIGINT(obj IrObject) *BigInt {
+	return obj.(*BigInt)
+}
+
+/*
+Returns the value of an Int or BigInt as *big.Int
+*/
+func toBigInt(obj IrObject) *big.Int {
+	switch value := obj.(type) {
+	case Int:
+		return big.NewInt(int64(value))
+	case *BigInt:
+		return value.Value
+	}
+
+	return nil
+}
+
+/*
+Represents integer numbers that do not fit in Int. It is never
+visible as a class on its own: both representations belong to
+IntClass, and arithmetic moves between them as needed.
+*/
+type BigInt struct {
+	*base
+
+	Value *big.Int
+}
+
+func (b *BigInt) String() string {
+	return b.Value.String()
+}
+
+func (b *BigInt) Float() Float {
+	value, _ := new(big.Float).SetInt(b.Value).Float64()
+	return Float(value)
+}
+
+func (b *BigInt) hashCode() Int {
+	hash := Int(b.Value.Sign())
+	for _, word := range b.Value.Bits() {
+		hash = 31*hash + Int(word)
+	}
+
+	return hash
+}
+
+/*
+Creates a new integer object, it is only a *BigInt
+when the value does not fit in Int
+*/
+func NewInteger(value *big.Int) IrObject {
+	if value.IsInt64() {
+		return Int(value.Int64())
+	}
+
+	return &BigInt{
+		Value: value,
+		base:  &base{class: IntClass},
+	}
+}
@@ -0,0 +1,134 @@
+package lang
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func bigFromString(t *testing.T, value string) IrObject {
+	t.Helper()
+
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		t.Fatalf("invalid big integer %q", value)
+	}
+
+	return NewInteger(n)
+}
+
+func Test_NewInteger(t *testing.T) {
+	if _, ok := NewInteger(big.NewInt(10)).(Int); !ok {
+		t.Error("expected small values to be demoted to Int")
+	}
+
+	value := bigFromString(t, "18446744073709551616")
+	if _, ok := value.(*BigInt); !ok {
+		t.Errorf("expected *BigInt, got %T", value)
+	}
+
+	if value.Class() != IntClass {
+		t.Errorf("expected class to be Int, got %s", value.Class())
+	}
+}
+
+func Test_intOverflowPromotion(t *testing.T) {
+	tests := []struct {
+		Scenario  string
+		Left      IrObject
+		Right     IrObject
+		operation func(Runtime, IrObject, IrObject) IrObject
+		Expected  string
+	}{
+		{
+			Scenario:  "add overflow",
+			Left:      Int(math.MaxInt),
+			Right:     Int(1),
+			operation: intAdd,
+			Expected:  "9223372036854775808",
+		},
+		{
+			Scenario:  "sub overflow",
+			Left:      Int(math.MinInt),
+			Right:     Int(1),
+			operation: intSub,
+			Expected:  "-9223372036854775809",
+		},
+		{
+			Scenario:  "multiply overflow",
+			Left:      Int(math.MaxInt),
+			Right:     Int(2),
+			operation: intMultiply,
+			Expected:  "18446744073709551614",
+		},
+		{
+			Scenario:  "divide overflow",
+			Left:      Int(math.MinInt),
+			Right:     Int(-1),
+			operation: intDivide,
+			Expected:  "9223372036854775808",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			result := test.operation(globalTestDummyRuntime, test.Left, test.Right)
+
+			if _, ok := result.(*BigInt); !ok {
+				t.Fatalf("expected result to be *BigInt, got %T", result)
+			}
+
+			inspect := intInspect(globalTestDummyRuntime, result)
+			assertEqual(t, inspect, NewString(test.Expected))
+		})
+	}
+}
+
+func Test_intDemotion(t *testing.T) {
+	big := bigFromString(t, "9223372036854775808")
+
+	result := intSub(globalTestDummyRuntime, big, Int(1))
+	assertEqual(t, result, Int(math.MaxInt))
+
+	result = intDivide(globalTestDummyRuntime, big, big)
+	assertEqual(t, result, Int(1))
+}
+
+func Test_bigIntFactorial(t *testing.T) {
+	var result IrObject = Int(1)
+	for i := 2; i <= 25; i++ {
+		result = intMultiply(globalTestDummyRuntime, result, Int(i))
+	}
+
+	inspect := intInspect(globalTestDummyRuntime, result)
+	assertEqual(t, inspect, NewString("15511210043330985984000000"))
+}
+
+func Test_bigIntComparison(t *testing.T) {
+	big := bigFromString(t, "9223372036854775808")
+	other := bigFromString(t, "9223372036854775808")
+
+	assertEqual(t, intEqual(globalTestDummyRuntime, big, other), True)
+	assertEqual(t, intEqual(globalTestDummyRuntime, big, Int(1)), False)
+	assertEqual(t, intGreat(globalTestDummyRuntime, big, Int(math.MaxInt)), True)
+	assertEqual(t, intLess(globalTestDummyRuntime, Int(math.MaxInt), big), True)
+	assertEqual(t, intLessEqual(globalTestDummyRuntime, big, other), True)
+	assertEqual(t, intGreatEqual(globalTestDummyRuntime, Int(0), big), False)
+}
+
+func Test_bigIntHash(t *testing.T) {
+	big := bigFromString(t, "9223372036854775808")
+	other := bigFromString(t, "9223372036854775808")
+
+	assertEqual(t, intHash(globalTestDummyRuntime, big), intHash(globalTestDummyRuntime, other))
+
+	h := NewHash()
+	hashInsert(globalTestDummyRuntime, h, big, Int(1))
+	assertEqual(t, hashLookup(globalTestDummyRuntime, h, other), Int(1))
+}
+
+func Test_bigIntUnarySub(t *testing.T) {
+	result := intUnarySub(globalTestDummyRuntime, Int(math.MinInt))
+	inspect := intInspect(globalTestDummyRuntime, result)
+	assertEqual(t, inspect, NewString("9223372036854775808"))
+}
@@ -6,6 +6,10 @@ import (
 )
 
 func call(rt Runtime, recv IrObject, name string, args ...IrObject) IrObject {
+	return callKeywords(rt, recv, name, nil, nil, args...)
+}
+
+func callKeywords(rt Runtime, recv IrObject, name string, names []string, values []IrObject, args ...IrObject) IrObject {
 	class := recv.Class()
 
 	method := class.LookupMethod(name)
@@ -16,9 +20,9 @@ func call(rt Runtime, recv IrObject, name string, args ...IrObject) IrObject {
 
 	switch method.methodType {
 	case GoFunction:
-		return method.Native().Invoke(rt, recv, args...)
+		return InvokeNative(rt, method.Native(), recv, names, values, args...)
 	case IrMethod:
-		return rt.Call(recv, method, args...)
+		return rt.CallKeywords(recv, method, names, values, args...)
 	default:
 		Unreachable()
 	}
@@ -3,13 +3,54 @@ package lang
 type CallInfo struct {
 	*base
 
-	name string
-	argc byte
+	name  string
+	argc  byte
+	splat bool
+
+	// names of the keyword arguments, their values are
+	// pushed after the positional ones in this order
+	keywords []string
+
+	// values the caller destructures
+	results byte
+
+	// called without a receiver
+	implicit bool
 }
 
 func (c *CallInfo) Name() string { return c.name }
 func (c *CallInfo) Argc() byte   { return c.argc }
 
+/*
+Reports whether the arguments were collected into
+a single array to be spread at the call site
+*/
+func (c *CallInfo) Splat() bool { return c.splat }
+
+func (c *CallInfo) Keywords() []string { return c.keywords }
+func (c *CallInfo) Results() byte      { return c.results }
+func (c *CallInfo) Implicit() bool     { return c.implicit }
+
+/*
+Marks a call without a receiver, when this does not respond
+to it the top-level functions are looked up instead
+*/
+func (c *CallInfo) MarkImplicit() { c.implicit = true }
+
+/*
+Makes the call leave count values on the stack, in the
+order UNPACK_ARRAY leaves them, instead of a single one
+*/
+func (c *CallInfo) ExpectResults(count byte) { c.results = count }
+
 func NewCallInfo(name string, argc byte) *CallInfo {
 	return &CallInfo{name: name, argc: argc}
 }
+
+func NewSplatCallInfo(name string) *CallInfo {
+	return &CallInfo{name: name, argc: 1, splat: true}
+}
+
+func NewKeywordCallInfo(name string, argc byte, splat bool, keywords []string) *CallInfo {
+	return &CallInfo{name: name, argc: argc, splat: splat, keywords: keywords}
+}
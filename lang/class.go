@@ -4,16 +4,24 @@ func CLASS(obj IrObject) *Class {
 	return obj.(*Class)
 }
 
-func classNew(rt Runtime, this IrObject, args ...IrObject) IrObject {
+func classNew(rt Runtime, this IrObject, names []string, values []IrObject, args ...IrObject) IrObject {
 	c := CLASS(this)
 	object := c.Alloc()
-	if val := call(rt, object, "init", args...); val == nil {
+	if val := callKeywords(rt, object, "init", names, values, args...); val == nil {
 		return nil
 	}
 
 	return object
 }
 
+/*
+Classes match their instances, including
+instances of their subclasses
+*/
+func classCaseEqual(rt Runtime, this IrObject, other IrObject) IrObject {
+	return Bool(other.Is(CLASS(this)))
+}
+
 var irClass *Class
 
 func InitClass() {
@@ -22,7 +30,8 @@ func InitClass() {
 	}
 
 	irClass = NewClass("Class", ObjectClass)
-	irClass.AddGoMethod("new", nArgs(classNew))
+	irClass.AddGoMethod("new", keywordArgs(classNew))
+	irClass.AddGoMethod("===", oneArg(classCaseEqual))
 }
 
 type Allocator func(*Class) IrObject
@@ -35,6 +44,9 @@ type Class struct {
 	fields    map[string]byte
 	methods   map[string]*Method
 	allocator func(*Class) IrObject
+
+	// the singleton instances of an enum, in declaration order
+	members []IrObject
 }
 
 func (c *Class) Name() string {
@@ -73,6 +85,19 @@ func (c *Class) AddField(name IrObject) {
 	c.fields[n] = byte(pos)
 }
 
+/*
+Returns the names of the fields in the order
+their values are kept in the object
+*/
+func (c *Class) fieldNames() []string {
+	names := make([]string, len(c.fields))
+	for name, pos := range c.fields {
+		names[pos] = name
+	}
+
+	return names
+}
+
 func (c *Class) AddMethod(name string, fun *Method) {
 	c.methods[name] = fun
 }
@@ -97,7 +97,7 @@ func Test_classNew_CallsInitMethod(t *testing.T) {
 
 	class.AddGoMethod("init", init)
 
-	classNew(globalTestDummyRuntime, class)
+	classNew(globalTestDummyRuntime, class, nil, nil)
 	if !called {
 		t.Error("expected init to be called")
 	}
@@ -105,7 +105,7 @@ func Test_classNew_CallsInitMethod(t *testing.T) {
 func Test_classNew_ReturnObjectFromTargetClass(t *testing.T) {
 	class := NewClass("Dummy", ObjectClass)
 
-	object := classNew(globalTestDummyRuntime, class)
+	object := classNew(globalTestDummyRuntime, class, nil, nil)
 
 	if object.Class() != class {
 		t.Errorf("expected class to be %s, got %s", class.name, object.Class().name)
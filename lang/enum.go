@@ -0,0 +1,114 @@
+package lang
+
+func ENUM(obj IrObject) *EnumValue {
+	return obj.(*EnumValue)
+}
+
+func enumName(rt Runtime, this IrObject) IrObject {
+	return NewString(ENUM(this).name)
+}
+
+func enumOrdinal(rt Runtime, this IrObject) IrObject {
+	return Int(ENUM(this).ordinal)
+}
+
+func enumValue(rt Runtime, this IrObject) IrObject {
+	return ENUM(this).value
+}
+
+func enumInspect(rt Runtime, this IrObject) IrObject {
+	return NewString(ENUM(this).String())
+}
+
+/*
+Lists the members of the enum in the order they were declared
+*/
+func enumValues(rt Runtime, this IrObject) IrObject {
+	members := CLASS(this).members
+
+	elements := make([]IrObject, len(members))
+	copy(elements, members)
+	return NewArray(elements)
+}
+
+func enumNew(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	rt.SetError(NewTypeError("can not instantiate enum %s", CLASS(this)))
+	return nil
+}
+
+var EnumClass *Class
+
+func InitEnum() {
+	if EnumClass != nil {
+		return
+	}
+
+	EnumClass = NewClass("Enum", ObjectClass)
+	EnumClass.AddGoMethod("name", zeroArgs(enumName))
+	EnumClass.AddGoMethod("ordinal", zeroArgs(enumOrdinal))
+	EnumClass.AddGoMethod("value", zeroArgs(enumValue))
+	EnumClass.AddGoMethod("inspect", zeroArgs(enumInspect))
+	EnumClass.AddGoMethod("to_str", zeroArgs(enumInspect))
+}
+
+/*
+A member of an enum, each one is the single instance of the enum
+with its name, so members are compared and hashed by identity
+*/
+type EnumValue struct {
+	*base
+
+	name    string
+	ordinal int
+	value   IrObject
+}
+
+func (e *EnumValue) Name() string {
+	return e.name
+}
+
+func (e *EnumValue) String() string {
+	return e.class.name + "." + e.name
+}
+
+/*
+Creates the class of an enum with a member for each name, values
+holds the value associated to each of them. The enum gets a class
+of its own answering values and refusing new
+*/
+func NewEnum(name string, names []string, values []IrObject) *Class {
+	meta := NewClass(name, irClass)
+	meta.AddGoMethod("values", zeroArgs(enumValues))
+	meta.AddGoMethod("new", nArgs(enumNew))
+
+	enum := NewClass(name, EnumClass)
+	enum.base = &base{class: meta}
+
+	enum.members = make([]IrObject, len(names))
+	for i, n := range names {
+		enum.members[i] = &EnumValue{
+			name:    n,
+			ordinal: i,
+			value:   values[i],
+			base:    &base{class: enum},
+		}
+	}
+
+	return enum
+}
+
+/*
+Defines the enum and each of its members as top-level
+constants, reporting false when the enum is already defined
+*/
+func (n *Namespace) DefineEnum(enum *Class) bool {
+	if !n.DefineConstant(enum.name, enum) {
+		return false
+	}
+
+	for _, member := range enum.members {
+		n.defined[enum.name+"."+ENUM(member).name] = member
+	}
+
+	return true
+}
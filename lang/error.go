@@ -12,6 +12,7 @@ var (
 	RegexpError       *Class
 	NoMethodError     *Class
 	ArgumentError     *Class
+	KeyError          *Class
 	ZeroDivisionError *Class
 )
 
@@ -57,6 +58,7 @@ func InitError() {
 	NameError = NewClass("NameError", Error)
 	RegexpError = NewClass("RegexpError", Error)
 	ArgumentError = NewClass("ArgumentError", Error)
+	KeyError = NewClass("KeyError", Error)
 	NoMethodError = NewClass("NoMethodError", NameError)
 	RuntimeError = NewClass("RuntimeError", Error)
 
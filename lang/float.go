@@ -17,6 +17,8 @@ func floatAdd(rt Runtime, lhs IrObject, rhs IrObject) IrObject {
 		return left + right
 	case Int:
 		return left + Float(right)
+	case *BigInt:
+		return left + right.Float()
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' + '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -32,6 +34,8 @@ func floatSub(rt Runtime, lhs IrObject, rhs IrObject) IrObject {
 		return left - right
 	case Int:
 		return left - Float(right)
+	case *BigInt:
+		return left - right.Float()
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' - '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -47,6 +51,8 @@ func floatMultiply(rt Runtime, lhs IrObject, rhs IrObject) IrObject {
 		return left * right
 	case Int:
 		return left * Float(right)
+	case *BigInt:
+		return left * right.Float()
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' * '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -62,6 +68,8 @@ func floatDivide(rt Runtime, lhs, rhs IrObject) IrObject {
 		return left / right
 	case Int:
 		return left / Float(right)
+	case *BigInt:
+		return left / right.Float()
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' / '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -77,6 +85,8 @@ func floatEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
 		return Bool(left == right)
 	case Int:
 		return Bool(left == Float(right))
+	case *BigInt:
+		return Bool(left == right.Float())
 	default:
 		return False
 	}
@@ -90,6 +100,8 @@ func floatGreat(rt Runtime, lhs IrObject, rhs IrObject) IrObject {
 		return Bool(left > right)
 	case Int:
 		return Bool(left > Float(right))
+	case *BigInt:
+		return Bool(left > right.Float())
 	default:
 		err := NewTypeError("invalid comparison (>) between '%s' and '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -104,6 +116,8 @@ func floatGreatEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
 		return Bool(left >= right)
 	case Int:
 		return Bool(left >= Float(right))
+	case *BigInt:
+		return Bool(left >= right.Float())
 	default:
 		err := NewTypeError("invalid comparison (>=) between '%s' and '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -119,6 +133,8 @@ func floatLess(rt Runtime, lhs IrObject, rhs IrObject) IrObject {
 		return Bool(left < right)
 	case Int:
 		return Bool(left < Float(right))
+	case *BigInt:
+		return Bool(left < right.Float())
 	default:
 		err := NewTypeError("invalid comparison (<) between '%s' and '%s'", FloatClass, right.Class())
 		rt.SetError(err)
@@ -134,6 +150,8 @@ func floatLessEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
 		return Bool(left <= right)
 	case Int:
 		return Bool(left <= Float(right))
+	case *BigInt:
+		return Bool(left <= right.Float())
 	default:
 		err := NewTypeError("invalid comparison (<=) between '%s' and '%s'", FloatClass, right.Class())
 		rt.SetError(err)
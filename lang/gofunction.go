@@ -4,6 +4,14 @@ type Native interface {
 	Invoke(Runtime, IrObject, ...IrObject) IrObject
 }
 
+/*
+Implemented by the Go methods taking keyword arguments,
+the others get them in a hash after the positional ones
+*/
+type KeywordNative interface {
+	InvokeKeywords(rt Runtime, recv IrObject, names []string, values []IrObject, argv ...IrObject) IrObject
+}
+
 func checkArity(rt Runtime, given, expected int) bool {
 	if given != expected {
 		rt.SetError(NewArityError(given, expected))
@@ -17,6 +25,7 @@ type nArgs func(Runtime, IrObject, ...IrObject) IrObject
 type zeroArgs func(Runtime, IrObject) IrObject
 type oneArg func(Runtime, IrObject, IrObject) IrObject
 type twoArgs func(Runtime, IrObject, IrObject, IrObject) IrObject
+type keywordArgs func(Runtime, IrObject, []string, []IrObject, ...IrObject) IrObject
 
 func (fn nArgs) Invoke(rt Runtime, recv IrObject, argv ...IrObject) IrObject {
 	return fn(rt, recv, argv...)
@@ -45,3 +54,31 @@ func (fn twoArgs) Invoke(rt Runtime, recv IrObject, argv ...IrObject) IrObject {
 
 	return fn(rt, recv, argv[0], argv[1])
 }
+
+func (fn keywordArgs) Invoke(rt Runtime, recv IrObject, argv ...IrObject) IrObject {
+	return fn(rt, recv, nil, nil, argv...)
+}
+
+func (fn keywordArgs) InvokeKeywords(rt Runtime, recv IrObject, names []string, values []IrObject, argv ...IrObject) IrObject {
+	return fn(rt, recv, names, values, argv...)
+}
+
+/*
+Invokes method passing the keyword arguments beside argv
+*/
+func InvokeNative(rt Runtime, method Native, recv IrObject, names []string, values []IrObject, argv ...IrObject) IrObject {
+	if len(names) == 0 {
+		return method.Invoke(rt, recv, argv...)
+	}
+
+	if native, ok := method.(KeywordNative); ok {
+		return native.InvokeKeywords(rt, recv, names, values, argv...)
+	}
+
+	hash := NewKeywordHash(rt, names, values)
+	if hash == nil {
+		return nil
+	}
+
+	return method.Invoke(rt, recv, append(argv, hash)...)
+}
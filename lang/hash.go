@@ -13,73 +13,281 @@ type entry struct {
 	key      IrObject
 	value    IrObject
 	next     *entry
+
+	// insertion order
+	before *entry
+	after  *entry
+
+	// deleted from the hash, iterators holding it skip it
+	removed bool
 }
 
-func retrieveHashCode(rt Runtime, obj IrObject) Int {
-	hash := call(rt, obj, "hash")
-	if hash == nil {
-		return 0
+func retrieveHashCode(rt Runtime, obj IrObject) (Int, bool) {
+	if symbol, ok := obj.(*Symbol); ok {
+		return symbol.hash, true
 	}
 
-	return INT(hash)
+	return callHash(rt, obj)
 }
 
-func hashLookup(rt Runtime, this IrObject, key IrObject) IrObject {
-	h := HASH(this)
+/*
+Calls hash on obj, a BigInt result is folded through its
+hash code and anything else but an Int is a TypeError
+*/
+func callHash(rt Runtime, obj IrObject) (Int, bool) {
+	hash := call(rt, obj, "hash")
+	switch code := hash.(type) {
+	case nil:
+		return 0, false
+	case Int:
+		return code, true
+	case *BigInt:
+		return code.hashCode(), true
+	}
 
-	hashCode := retrieveHashCode(rt, key)
-	length := Int(len(h.table))
-	index := (hashCode & 0x7FFFFFFF) % length
+	rt.SetError(NewTypeError("hash of %s returned %s, expected Int", obj.Class(), hash.Class()))
+	return 0, false
+}
+
+/*
+Looks up the entry for key, ok is false when
+calling == on one of the keys failed
+*/
+func (h *Hash) find(rt Runtime, hashCode Int, key IrObject) (found *entry, ok bool) {
+	index := (hashCode & 0x7FFFFFFF) % Int(len(h.table))
 
 	for entry := h.table[index]; entry != nil; entry = entry.next {
+		if entry.hashCode != hashCode {
+			continue
+		}
+
+		// symbols are only equal to themselves
+		if _, ok := key.(*Symbol); ok {
+			if entry.key == key {
+				return entry, true
+			}
+
+			continue
+		}
+
 		equal := call(rt, entry.key, "==", key)
 		if equal == nil {
-			return nil
+			return nil, false
 		}
 
-		if entry.hashCode == hashCode && BOOL(equal) {
-			return entry.value
+		if BOOL(equal) {
+			return entry, true
 		}
 	}
 
-	return None
+	return nil, true
+}
+
+func hashLookup(rt Runtime, this IrObject, key IrObject) IrObject {
+	h := HASH(this)
+
+	hashCode, ok := retrieveHashCode(rt, key)
+	if !ok {
+		return nil
+	}
+
+	entry, ok := h.find(rt, hashCode, key)
+	if !ok {
+		return nil
+	}
+
+	if entry == nil {
+		return None
+	}
+
+	return entry.value
+}
+
+func hashFetch(rt Runtime, this IrObject, args ...IrObject) IrObject {
+	if len(args) != 1 && len(args) != 2 {
+		rt.SetError(NewArityError(len(args), 2))
+		return nil
+	}
+
+	h := HASH(this)
+	key := args[0]
+
+	hashCode, ok := retrieveHashCode(rt, key)
+	if !ok {
+		return nil
+	}
+
+	entry, ok := h.find(rt, hashCode, key)
+	if !ok {
+		return nil
+	}
+
+	if entry != nil {
+		return entry.value
+	}
+
+	if len(args) == 2 {
+		return args[1]
+	}
+
+	inspect := call(rt, key, "inspect")
+	if inspect == nil {
+		return nil
+	}
+
+	rt.SetError(NewError("key not found: %s", KeyError, inspect))
+	return nil
 }
 
 func hashInsert(rt Runtime, this IrObject, key IrObject, value IrObject) IrObject {
 	h := HASH(this)
 
-	hashCode := retrieveHashCode(rt, key)
-	length := Int(len(h.table))
-	index := (hashCode & 0x7FFFFFFF) % length
+	hashCode, ok := retrieveHashCode(rt, key)
+	if !ok {
+		return nil
+	}
 
-	for entry := h.table[index]; entry != nil; entry = entry.next {
-		equal := call(rt, entry.key, "==", key)
+	entry, ok := h.find(rt, hashCode, key)
+	if !ok {
+		return nil
+	}
+
+	if entry != nil {
+		entry.value = value
+		return True
+	}
+
+	h.addEntry(hashCode, key, value)
+	return True
+}
+
+func hashDelete(rt Runtime, this IrObject, key IrObject) IrObject {
+	h := HASH(this)
+
+	hashCode, ok := retrieveHashCode(rt, key)
+	if !ok {
+		return nil
+	}
+
+	entry, ok := h.find(rt, hashCode, key)
+	if !ok {
+		return nil
+	}
+
+	if entry == nil {
+		return None
+	}
+
+	h.removeEntry(entry)
+	return entry.value
+}
+
+func hashClear(rt Runtime, this IrObject) IrObject {
+	h := HASH(this)
+
+	for i := range h.table {
+		h.table[i] = nil
+	}
+
+	for e := h.head; e != nil; e = e.after {
+		e.removed = true
+	}
+
+	h.head = nil
+	h.tail = nil
+	h.count = 0
+	return h
+}
+
+/*
+Returns a new hash with the entries of both hashes,
+values from other win when the same key is in both
+*/
+func hashMerge(rt Runtime, this IrObject, other IrObject) IrObject {
+	o, ok := other.(*Hash)
+	if !ok {
+		rt.SetError(NewTypeError("no implicit conversion of %s into Hash", other.Class()))
+		return nil
+	}
+
+	result := NewHash()
+	for _, h := range []*Hash{HASH(this), o} {
+		for entry := h.head; entry != nil; entry = entry.after {
+			if hashInsert(rt, result, entry.key, entry.value) == nil {
+				return nil
+			}
+		}
+	}
+
+	return result
+}
+
+/*
+Two hashes are equal when they have the same keys mapped
+to equal values, regardless of their insertion order
+*/
+func hashEqual(rt Runtime, this IrObject, other IrObject) IrObject {
+	o, ok := other.(*Hash)
+	if !ok {
+		return False
+	}
+
+	h := HASH(this)
+	if h == o {
+		return True
+	}
+
+	if h.count != o.count {
+		return False
+	}
+
+	for entry := h.head; entry != nil; entry = entry.after {
+		found, ok := o.find(rt, entry.hashCode, entry.key)
+		if !ok {
+			return nil
+		}
+
+		if found == nil {
+			return False
+		}
+
+		equal := call(rt, entry.value, "==", found.value)
 		if equal == nil {
 			return nil
 		}
 
-		if entry.hashCode == hashCode && BOOL(equal) {
-			entry.value = value
-			return True
+		if !IsTruthy(equal) {
+			return False
 		}
 	}
 
-	h.addEntry(rt, hashCode, key, value, index)
 	return True
 }
 
-func hashKeys(rt Runtime, this IrObject) IrObject {
+func hashHash(rt Runtime, this IrObject) IrObject {
 	h := HASH(this)
 
-	var keys []IrObject
-	for _, entry := range h.table {
-		if entry == nil {
-			continue
+	var hash Int
+	for entry := h.head; entry != nil; entry = entry.after {
+		value, ok := callHash(rt, entry.value)
+		if !ok {
+			return nil
 		}
 
-		for ; entry != nil; entry = entry.next {
-			keys = append(keys, entry.key)
-		}
+		// entries are combined with a sum, so
+		// insertion order does not change the hash
+		hash += 31*entry.hashCode + value
+	}
+
+	return hash
+}
+
+func hashKeys(rt Runtime, this IrObject) IrObject {
+	h := HASH(this)
+
+	keys := make([]IrObject, 0, h.count)
+	for entry := h.head; entry != nil; entry = entry.after {
+		keys = append(keys, entry.key)
 	}
 
 	return NewArray(keys)
@@ -88,15 +296,9 @@ func hashKeys(rt Runtime, this IrObject) IrObject {
 func hashValues(rt Runtime, this IrObject) IrObject {
 	h := HASH(this)
 
-	var values []IrObject
-	for _, entry := range h.table {
-		if entry == nil {
-			continue
-		}
-
-		for ; entry != nil; entry = entry.next {
-			values = append(values, entry.value)
-		}
+	values := make([]IrObject, 0, h.count)
+	for entry := h.head; entry != nil; entry = entry.after {
+		values = append(values, entry.value)
 	}
 
 	return NewArray(values)
@@ -111,29 +313,29 @@ func hashInspect(rt Runtime, this IrObject) IrObject {
 
 	var buf strings.Builder
 	buf.WriteByte('{')
-	for _, entry := range h.table {
-		if entry == nil {
-			continue
+	for entry := h.head; entry != nil; entry = entry.after {
+		if entry != h.head {
+			buf.WriteString(", ")
 		}
 
-		for ; entry != nil; entry = entry.next {
-			if buf.Len() > 1 { // 1 is open {
-				buf.WriteString(", ")
-			}
-			var val IrObject
+		var val IrObject
+		if symbol, ok := entry.key.(*Symbol); ok {
+			// printed as the shorthand written in literals, name: 1
+			buf.WriteString(symbol.name)
+		} else {
 			if val = call(rt, entry.key, "inspect"); val == nil {
 				return nil
 			}
 
 			buf.Write(unwrapString(val))
-			buf.WriteString(": ")
-
-			if val = call(rt, entry.value, "inspect"); val == nil {
-				return nil
-			}
+		}
+		buf.WriteString(": ")
 
-			buf.Write(unwrapString(val))
+		if val = call(rt, entry.value, "inspect"); val == nil {
+			return nil
 		}
+
+		buf.Write(unwrapString(val))
 	}
 
 	buf.WriteByte('}')
@@ -145,6 +347,10 @@ func hashSize(rt Runtime, this IrObject) IrObject {
 	return HASH(this).count
 }
 
+func hashEmpty(rt Runtime, this IrObject) IrObject {
+	return Bool(HASH(this).count == 0)
+}
+
 func hashValuesAt(rt Runtime, this IrObject, keys ...IrObject) IrObject {
 	result := make([]IrObject, len(keys))
 	if len(keys) == 0 {
@@ -153,7 +359,9 @@ func hashValuesAt(rt Runtime, this IrObject, keys ...IrObject) IrObject {
 
 	h := HASH(this)
 	for i, key := range keys {
-		result[i] = hashLookup(rt, h, key)
+		if result[i] = hashLookup(rt, h, key); result[i] == nil {
+			return nil
+		}
 	}
 
 	return NewArray(result)
@@ -162,22 +370,17 @@ func hashValuesAt(rt Runtime, this IrObject, keys ...IrObject) IrObject {
 func hashHasKey(rt Runtime, this, key IrObject) IrObject {
 	h := HASH(this)
 
-	hashCode := retrieveHashCode(rt, key)
-	length := Int(len(h.table))
-	index := (hashCode & 0x7FFFFFFF) % length
-
-	for entry := h.table[index]; entry != nil; entry = entry.next {
-		equal := call(rt, entry.key, "==", key)
-		if equal == nil {
-			return nil
-		}
+	hashCode, ok := retrieveHashCode(rt, key)
+	if !ok {
+		return nil
+	}
 
-		if entry.hashCode == hashCode && BOOL(equal) {
-			return True
-		}
+	entry, ok := h.find(rt, hashCode, key)
+	if !ok {
+		return nil
 	}
 
-	return False
+	return Bool(entry != nil)
 }
 
 var HashClass *Class
@@ -189,13 +392,22 @@ func InitHash() {
 
 	HashClass = NewClass("Hash", ObjectClass)
 
+	HashClass.AddGoMethod("==", oneArg(hashEqual))
+	HashClass.AddGoMethod("hash", zeroArgs(hashHash))
 	HashClass.AddGoMethod("put", twoArgs(hashInsert))
 	HashClass.AddGoMethod("insert", twoArgs(hashInsert))
+	HashClass.AddGoMethod("set", twoArgs(hashInsert))
 	HashClass.AddGoMethod("get", oneArg(hashLookup))
+	HashClass.AddGoMethod("fetch", nArgs(hashFetch))
+	HashClass.AddGoMethod("delete", oneArg(hashDelete))
+	HashClass.AddGoMethod("clear", zeroArgs(hashClear))
+	HashClass.AddGoMethod("merge", oneArg(hashMerge))
 	HashClass.AddGoMethod("key?", oneArg(hashHasKey))
+	HashClass.AddGoMethod("include?", oneArg(hashHasKey))
 	HashClass.AddGoMethod("keys", zeroArgs(hashKeys))
 	HashClass.AddGoMethod("values", zeroArgs(hashValues))
 	HashClass.AddGoMethod("size", zeroArgs(hashSize))
+	HashClass.AddGoMethod("empty?", zeroArgs(hashEmpty))
 	HashClass.AddGoMethod("values_at", nArgs(hashValuesAt))
 	HashClass.AddGoMethod("inspect", zeroArgs(hashInspect))
 	HashClass.AddGoMethod("to_str", zeroArgs(hashInspect))
@@ -210,32 +422,92 @@ func NewHash() *Hash {
 	}
 }
 
+/*
+Entries are chained in buckets by next, and linked by
+before and after in insertion order, which is the order
+used to iterate over the hash
+*/
 type Hash struct {
 	*base
 
 	table      []*entry
+	head       *entry
+	tail       *entry
 	threshold  Int
 	count      Int
 	loadFactor float32
 }
 
-func (h *Hash) addEntry(rt Runtime, hashCode Int, key IrObject, value IrObject, index Int) {
+/*
+Collects the keyword arguments given to a Go method
+that does not take them, keyed by their symbols
+*/
+func NewKeywordHash(rt Runtime, names []string, values []IrObject) *Hash {
+	h := NewHash()
+	for i, name := range names {
+		if hashInsert(rt, h, Intern(name), values[i]) == nil {
+			return nil
+		}
+	}
+
+	return h
+}
+
+func (h *Hash) addEntry(hashCode Int, key IrObject, value IrObject) {
 	if h.count >= h.threshold {
 		h.rehash()
-		hashCode := retrieveHashCode(rt, key)
-		index = (hashCode & 0x7FFFFFFF) % Int(len(h.table))
 	}
 
-	e := h.table[index]
-	h.table[index] = &entry{
+	index := (hashCode & 0x7FFFFFFF) % Int(len(h.table))
+	e := &entry{
 		hashCode: hashCode,
 		key:      key,
 		value:    value,
-		next:     e,
+		next:     h.table[index],
+		before:   h.tail,
 	}
+
+	if h.tail == nil {
+		h.head = e
+	} else {
+		h.tail.after = e
+	}
+
+	h.table[index] = e
+	h.tail = e
 	h.count++
 }
 
+func (h *Hash) removeEntry(e *entry) {
+	index := (e.hashCode & 0x7FFFFFFF) % Int(len(h.table))
+
+	if h.table[index] == e {
+		h.table[index] = e.next
+	} else {
+		prev := h.table[index]
+		for prev.next != e {
+			prev = prev.next
+		}
+
+		prev.next = e.next
+	}
+
+	if e.before == nil {
+		h.head = e.after
+	} else {
+		e.before.after = e.after
+	}
+
+	if e.after == nil {
+		h.tail = e.before
+	} else {
+		e.after.before = e.before
+	}
+
+	e.removed = true
+	h.count--
+}
+
 func (h *Hash) BulkInsert(rt Runtime, elements []IrObject) {
 	for i := 0; i < len(elements); i += 2 {
 		if res := hashInsert(rt, h, elements[i], elements[i+1]); res == nil {
@@ -1,6 +1,9 @@
 package lang
 
-import "testing"
+import (
+	"math/big"
+	"testing"
+)
 
 func Test_hashSize(t *testing.T) {
 	h := NewHash()
@@ -14,6 +17,42 @@ func Test_hashSize(t *testing.T) {
 	}
 }
 
+func Test_NewKeywordHash(t *testing.T) {
+	h := NewKeywordHash(globalTestDummyRuntime, []string{"tls", "port"}, []IrObject{True, Int(80)})
+
+	assertEqual(t, hashSize(globalTestDummyRuntime, h), Int(2))
+	assertEqual(t, hashLookup(globalTestDummyRuntime, h, Intern("tls")), True)
+	assertEqual(t, hashLookup(globalTestDummyRuntime, h, Intern("port")), Int(80))
+}
+
+func Test_InvokeNative(t *testing.T) {
+	names, values := []string{"port"}, []IrObject{Int(80)}
+
+	var given []IrObject
+	plain := nArgs(func(rt Runtime, this IrObject, args ...IrObject) IrObject {
+		given = args
+		return None
+	})
+
+	InvokeNative(globalTestDummyRuntime, plain, None, names, values, Int(1))
+	if len(given) != 2 {
+		t.Fatalf("expected the keywords in a hash after the arguments, got %v", given)
+	}
+
+	assertEqual(t, hashLookup(globalTestDummyRuntime, given[1].(*Hash), Intern("port")), Int(80))
+
+	var keywords []string
+	taking := keywordArgs(func(rt Runtime, this IrObject, names []string, values []IrObject, args ...IrObject) IrObject {
+		keywords, given = names, args
+		return None
+	})
+
+	InvokeNative(globalTestDummyRuntime, taking, None, names, values, Int(1))
+	if len(keywords) != 1 || keywords[0] != "port" || len(given) != 1 {
+		t.Errorf("expected the keywords beside the arguments, got %v and %v", keywords, given)
+	}
+}
+
 func Test_hashInsert(t *testing.T) {
 	h := NewHash()
 
@@ -91,3 +130,136 @@ func Test_hashValuesAt(t *testing.T) {
 		t.Errorf("expected keys to be %+v, got %+v", keys, ret)
 	}
 }
+
+func newTestHash(t *testing.T, pairs ...IrObject) *Hash {
+	t.Helper()
+
+	h := NewHash()
+	h.BulkInsert(globalTestDummyRuntime, pairs)
+	return h
+}
+
+func Test_hashKeys_insertionOrder(t *testing.T) {
+	h := NewHash()
+
+	// enough entries to go through a few rehashes
+	for i := 100; i > 0; i-- {
+		hashInsert(globalTestDummyRuntime, h, Int(i), Int(i))
+	}
+
+	for i, key := range ARRAY(hashKeys(globalTestDummyRuntime, h)).Elements {
+		assertEqual(t, key, Int(100-i))
+	}
+}
+
+func Test_hashDelete(t *testing.T) {
+	h := newTestHash(t, NewString("a"), Int(1), NewString("b"), Int(2), NewString("c"), Int(3))
+
+	assertEqual(t, hashDelete(globalTestDummyRuntime, h, NewString("b")), Int(2))
+	assertEqual(t, hashSize(globalTestDummyRuntime, h), Int(2))
+	assertEqual(t, hashHasKey(globalTestDummyRuntime, h, NewString("b")), False)
+	assertEqual(t, hashInspect(globalTestDummyRuntime, h), NewString(`{"a": 1, "c": 3}`))
+
+	if value := hashDelete(globalTestDummyRuntime, h, NewString("z")); value != None {
+		t.Errorf("expected None, got %v", value)
+	}
+
+	hashDelete(globalTestDummyRuntime, h, NewString("c"))
+	hashDelete(globalTestDummyRuntime, h, NewString("a"))
+	hashInsert(globalTestDummyRuntime, h, NewString("d"), Int(4))
+	assertEqual(t, hashInspect(globalTestDummyRuntime, h), NewString(`{"d": 4}`))
+}
+
+func Test_hashClear(t *testing.T) {
+	h := newTestHash(t, NewString("a"), Int(1))
+
+	hashClear(globalTestDummyRuntime, h)
+	assertEqual(t, hashEmpty(globalTestDummyRuntime, h), True)
+	assertEqual(t, hashInspect(globalTestDummyRuntime, h), NewString("{}"))
+
+	if value := hashLookup(globalTestDummyRuntime, h, NewString("a")); value != None {
+		t.Errorf("expected None, got %v", value)
+	}
+}
+
+func Test_hashFetch(t *testing.T) {
+	h := newTestHash(t, NewString("a"), Int(1))
+
+	assertEqual(t, hashFetch(globalTestDummyRuntime, h, NewString("a")), Int(1))
+	assertEqual(t, hashFetch(globalTestDummyRuntime, h, NewString("b"), Int(0)), Int(0))
+
+	rt := new(dummyRuntime)
+	if value := hashFetch(rt, h, NewString("b")); value != nil {
+		t.Fatalf("expected nil, got %v", value)
+	}
+
+	if rt.err == nil || rt.err.message != `key not found: "b"` {
+		t.Errorf("expected KeyError, got %v", rt.err)
+	}
+}
+
+func Test_hashMerge(t *testing.T) {
+	h := newTestHash(t, NewString("a"), Int(1), NewString("b"), Int(2))
+	other := newTestHash(t, NewString("b"), Int(20), NewString("c"), Int(3))
+
+	merged := hashMerge(globalTestDummyRuntime, h, other)
+	assertEqual(t, hashInspect(globalTestDummyRuntime, merged), NewString(`{"a": 1, "b": 20, "c": 3}`))
+	assertEqual(t, hashSize(globalTestDummyRuntime, h), Int(2))
+}
+
+func Test_hashEqual(t *testing.T) {
+	h := newTestHash(t, NewString("a"), Int(1), NewString("b"), Int(2))
+
+	tests := []struct {
+		Scenario string
+		Other    IrObject
+		Expected Bool
+	}{
+		{Scenario: "different order", Other: newTestHash(t, NewString("b"), Int(2), NewString("a"), Int(1)), Expected: true},
+		{Scenario: "different value", Other: newTestHash(t, NewString("a"), Int(1), NewString("b"), Int(3)), Expected: false},
+		{Scenario: "different size", Other: newTestHash(t, NewString("a"), Int(1)), Expected: false},
+		{Scenario: "not a hash", Other: Int(1), Expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			assertEqual(t, hashEqual(globalTestDummyRuntime, h, test.Other), test.Expected)
+		})
+	}
+
+	other := newTestHash(t, NewString("b"), Int(2), NewString("a"), Int(1))
+	assertEqual(t, hashHash(globalTestDummyRuntime, h), hashHash(globalTestDummyRuntime, other))
+}
+
+func Test_hashSymbolKeys(t *testing.T) {
+	h := NewHash()
+	hashInsert(globalTestDummyRuntime, h, Intern("name"), NewString("ada"))
+	hashInsert(globalTestDummyRuntime, h, NewString("name"), NewString("str"))
+
+	assertEqual(t, hashLookup(globalTestDummyRuntime, h, Intern("name")), NewString("ada"))
+	assertEqual(t, hashLookup(globalTestDummyRuntime, h, NewString("name")), NewString("str"))
+	assertEqual(t, hashInspect(globalTestDummyRuntime, h), NewString(`{name: "ada", "name": "str"}`))
+}
+
+func Test_hashInsert_withUserDefinedHash(t *testing.T) {
+	huge := NewInteger(new(big.Int).Lsh(big.NewInt(1), 80))
+
+	class := NewClass("Key", ObjectClass)
+	class.AddGoMethod("hash", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return huge
+	}))
+
+	h := NewHash()
+	key := class.Alloc()
+	hashInsert(globalTestDummyRuntime, h, key, Int(1))
+	assertEqual(t, hashLookup(globalTestDummyRuntime, h, key), Int(1))
+
+	class.AddGoMethod("hash", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return NewString("nope")
+	}))
+
+	rt := new(dummyRuntime)
+	if result := hashInsert(rt, NewHash(), class.Alloc(), Int(1)); result != nil || rt.err == nil || !rt.err.Is(TypeError) {
+		t.Errorf("expected a TypeError, got %v", result)
+	}
+}
@@ -2,6 +2,8 @@ package lang
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"strings"
 )
 
@@ -10,8 +12,11 @@ func INT(obj IrObject) Int {
 }
 
 func toInt(value IrObject) (Int, *ErrorObject) {
-	if i, ok := value.(Int); ok {
+	switch i := value.(type) {
+	case Int:
 		return i, nil
+	case *BigInt:
+		return 0, NewError("integer %s too big to convert into Int", ArgumentError, i)
 	}
 
 	var mesg = new(strings.Builder)
@@ -19,13 +24,68 @@ func toInt(value IrObject) (Int, *ErrorObject) {
 	return 0, NewError(mesg.String(), TypeError)
 }
 
+func intToFloat(obj IrObject) Float {
+	if big, ok := obj.(*BigInt); ok {
+		return big.Float()
+	}
+
+	return Float(INT(obj))
+}
+
+/*
+Compares two integers regardless of their representation
+*/
+func intCompare(lhs, rhs IrObject) int {
+	left, isInt := lhs.(Int)
+	right, bothInt := rhs.(Int)
+
+	if isInt && bothInt {
+		switch {
+		case left < right:
+			return -1
+		case left > right:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return toBigInt(lhs).Cmp(toBigInt(rhs))
+}
+
+func addOverflows(left, right Int) bool {
+	sum := left + right
+	return (sum > left) != (right > 0)
+}
+
+func subOverflows(left, right Int) bool {
+	diff := left - right
+	return (diff < left) != (right > 0)
+}
+
+func mulOverflows(left, right Int) bool {
+	if left == 0 || right == 0 {
+		return false
+	}
+
+	if (left == -1 && right == math.MinInt) || (right == -1 && left == math.MinInt) {
+		return true
+	}
+
+	return (left*right)/right != left
+}
+
 func intAdd(rt Runtime, this IrObject, rhs IrObject) IrObject {
-	left := INT(this)
 	switch right := rhs.(type) {
 	case Int:
-		return left + right
+		if left, ok := this.(Int); ok && !addOverflows(left, right) {
+			return left + right
+		}
+		return NewInteger(new(big.Int).Add(toBigInt(this), toBigInt(right)))
+	case *BigInt:
+		return NewInteger(new(big.Int).Add(toBigInt(this), right.Value))
 	case Float:
-		return Float(left) + right
+		return intToFloat(this) + right
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' + '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -34,12 +94,16 @@ func intAdd(rt Runtime, this IrObject, rhs IrObject) IrObject {
 }
 
 func intSub(rt Runtime, this IrObject, rhs IrObject) IrObject {
-	left := INT(this)
 	switch right := rhs.(type) {
 	case Int:
-		return left - right
+		if left, ok := this.(Int); ok && !subOverflows(left, right) {
+			return left - right
+		}
+		return NewInteger(new(big.Int).Sub(toBigInt(this), toBigInt(right)))
+	case *BigInt:
+		return NewInteger(new(big.Int).Sub(toBigInt(this), right.Value))
 	case Float:
-		return Float(left) - right
+		return intToFloat(this) - right
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' - '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -48,12 +112,16 @@ func intSub(rt Runtime, this IrObject, rhs IrObject) IrObject {
 }
 
 func intMultiply(rt Runtime, lhs, rhs IrObject) IrObject {
-	left := INT(lhs)
 	switch right := rhs.(type) {
 	case Int:
-		return left * right
+		if left, ok := lhs.(Int); ok && !mulOverflows(left, right) {
+			return left * right
+		}
+		return NewInteger(new(big.Int).Mul(toBigInt(lhs), toBigInt(right)))
+	case *BigInt:
+		return NewInteger(new(big.Int).Mul(toBigInt(lhs), right.Value))
 	case Float:
-		return Float(left) * right
+		return intToFloat(lhs) * right
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' * '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -62,7 +130,6 @@ func intMultiply(rt Runtime, lhs, rhs IrObject) IrObject {
 }
 
 func intDivide(rt Runtime, lhs, rhs IrObject) IrObject {
-	left := INT(lhs)
 	switch right := rhs.(type) {
 	case Int:
 		if right == 0 {
@@ -70,9 +137,15 @@ func intDivide(rt Runtime, lhs, rhs IrObject) IrObject {
 			rt.SetError(err)
 			return nil
 		}
-		return left / right
+
+		if left, ok := lhs.(Int); ok && !(left == math.MinInt && right == -1) {
+			return left / right
+		}
+		return NewInteger(new(big.Int).Quo(toBigInt(lhs), toBigInt(right)))
+	case *BigInt:
+		return NewInteger(new(big.Int).Quo(toBigInt(lhs), right.Value))
 	case Float:
-		return Float(left) / right
+		return intToFloat(lhs) / right
 	default:
 		err := NewTypeError("unsupported operand type(s): '%s' / '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -81,24 +154,28 @@ func intDivide(rt Runtime, lhs, rhs IrObject) IrObject {
 }
 
 func intEqual(rt Runtime, lhs, rhs IrObject) IrObject {
-	left := INT(lhs)
 	switch right := rhs.(type) {
-	case Int:
-		return NewBoolean(left == right)
+	case Int, *BigInt:
+		return NewBoolean(intCompare(lhs, right) == 0)
 	case Float:
-		return NewBoolean(left == Int(right))
+		if left, ok := lhs.(Int); ok {
+			return NewBoolean(left == Int(right))
+		}
+		return NewBoolean(intToFloat(lhs) == right)
 	default:
 		return False
 	}
 }
 
 func intGreat(rt Runtime, this IrObject, rhs IrObject) IrObject {
-	left := INT(this)
 	switch right := rhs.(type) {
-	case Int:
-		return NewBoolean(left > right)
+	case Int, *BigInt:
+		return NewBoolean(intCompare(this, right) > 0)
 	case Float:
-		return NewBoolean(left > Int(right))
+		if left, ok := this.(Int); ok {
+			return NewBoolean(left > Int(right))
+		}
+		return NewBoolean(intToFloat(this) > right)
 	default:
 		err := NewTypeError("invalid comparison (>) between '%s' and '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -107,12 +184,14 @@ func intGreat(rt Runtime, this IrObject, rhs IrObject) IrObject {
 }
 
 func intGreatEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
-	left := INT(this)
 	switch right := rhs.(type) {
-	case Int:
-		return NewBoolean(left >= right)
+	case Int, *BigInt:
+		return NewBoolean(intCompare(this, right) >= 0)
 	case Float:
-		return NewBoolean(left >= Int(right))
+		if left, ok := this.(Int); ok {
+			return NewBoolean(left >= Int(right))
+		}
+		return NewBoolean(intToFloat(this) >= right)
 	default:
 		err := NewTypeError("invalid comparison (>=) between '%s' and '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -121,12 +200,14 @@ func intGreatEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
 }
 
 func intLess(rt Runtime, this IrObject, rhs IrObject) IrObject {
-	left := INT(this)
 	switch right := rhs.(type) {
-	case Int:
-		return NewBoolean(left < right)
+	case Int, *BigInt:
+		return NewBoolean(intCompare(this, right) < 0)
 	case Float:
-		return NewBoolean(left < Int(right))
+		if left, ok := this.(Int); ok {
+			return NewBoolean(left < Int(right))
+		}
+		return NewBoolean(intToFloat(this) < right)
 	default:
 		err := NewTypeError("invalid comparison (<) between '%s' and '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -135,12 +216,14 @@ func intLess(rt Runtime, this IrObject, rhs IrObject) IrObject {
 }
 
 func intLessEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
-	left := INT(this)
 	switch right := rhs.(type) {
-	case Int:
-		return NewBoolean(left <= right)
+	case Int, *BigInt:
+		return NewBoolean(intCompare(this, right) <= 0)
 	case Float:
-		return NewBoolean(left <= Int(right))
+		if left, ok := this.(Int); ok {
+			return NewBoolean(left <= Int(right))
+		}
+		return NewBoolean(intToFloat(this) <= right)
 	default:
 		err := NewTypeError("invalid comparison (<=) between '%s' and '%s'", IntClass, right.Class())
 		rt.SetError(err)
@@ -153,15 +236,27 @@ func intUnaryAdd(rt Runtime, this IrObject) IrObject {
 }
 
 func intUnarySub(rt Runtime, this IrObject) IrObject {
-	return -INT(this)
+	if value, ok := this.(Int); ok && value != math.MinInt {
+		return -value
+	}
+
+	return NewInteger(new(big.Int).Neg(toBigInt(this)))
 }
 
 func intInspect(rt Runtime, this IrObject) IrObject {
+	if big, ok := this.(*BigInt); ok {
+		return NewString(big.String())
+	}
+
 	inspect := fmt.Sprintf("%d", INT(this))
 	return NewString(inspect)
 }
 
 func intHash(rt Runtime, this IrObject) IrObject {
+	if big, ok := this.(*BigInt); ok {
+		return big.hashCode()
+	}
+
 	return this
 }
 
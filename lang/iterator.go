@@ -1,26 +1,255 @@
 package lang
 
-type Iterator struct {
+import (
+	"unicode/utf8"
+)
+
+func ITERATOR(obj IrObject) *Iterator {
+	return obj.(*Iterator)
+}
+
+/*
+Sources produce one element per call to next, returning
+StopIteration once exhausted and nil when an error was set
+*/
+type iterable interface {
+	next(rt Runtime) IrObject
+}
+
+type arrayIterator struct {
 	index int
 	list  *Array
+}
+
+func (a *arrayIterator) next(rt Runtime) IrObject {
+	if a.index >= a.list.Length() {
+		return StopIteration
+	}
+
+	item := a.list.At(a.index)
+	a.index++
+	return item
+}
+
+type rangeIterator struct {
+	current Int
+	last    Int
+	step    Int
+	done    bool
+}
+
+func (r *rangeIterator) hasNext() bool {
+	return !r.done
+}
+
+func (r *rangeIterator) next(rt Runtime) IrObject {
+	if !r.hasNext() {
+		return StopIteration
+	}
+
+	// stops on the last element, stepping past it could overflow
+	item := r.current
+	if item == r.last {
+		r.done = true
+	} else {
+		r.current += r.step
+	}
+
+	return item
+}
+
+func newRangeIterator(r *Range) *rangeIterator {
+	steps, ok := r.steps()
+	return &rangeIterator{current: r.Start, last: r.last(steps), step: r.Step, done: !ok}
+}
+
+type stringIterator struct {
+	index int
+	value []byte
+}
+
+func (s *stringIterator) next(rt Runtime) IrObject {
+	if s.index >= len(s.value) {
+		return StopIteration
+	}
+
+	_, size := utf8.DecodeRune(s.value[s.index:])
+	char := string(s.value[s.index : s.index+size])
+	s.index += size
+	return NewString(char)
+}
+
+/*
+Walks the hash entries in insertion order, yielding [key, value]
+pairs. Removed entries keep their links to the entries after them,
+so the walk goes on past the ones deleted while iterating
+*/
+type hashIterator struct {
+	current *entry
+}
+
+func (h *hashIterator) next(rt Runtime) IrObject {
+	e := h.current
+	for e != nil && e.removed {
+		e = e.after
+	}
+
+	if e == nil {
+		return StopIteration
+	}
+
+	h.current = e.after
+	return NewArray([]IrObject{e.key, e.value})
+}
+
+/*
+Adapts objects implementing the iteration protocol: when the
+object responds to has_next? it is asked before every call to
+next, otherwise next is called until it returns StopIteration
+*/
+type objectIterator struct {
+	object IrObject
+}
 
+func (o *objectIterator) next(rt Runtime) IrObject {
+	if o.object.Class().LookupMethod("has_next?") != nil {
+		hasNext := call(rt, o.object, "has_next?")
+		if hasNext == nil {
+			return nil
+		}
+
+		if !IsTruthy(hasNext) {
+			return StopIteration
+		}
+	}
+
+	return call(rt, o.object, "next")
+}
+
+func iteratorHasNext(rt Runtime, this IrObject) IrObject {
+	return ITERATOR(this).HasNext(rt)
+}
+
+func iteratorNext(rt Runtime, this IrObject) IrObject {
+	return ITERATOR(this).Next(rt)
+}
+
+func iteratorSelf(rt Runtime, this IrObject) IrObject {
+	return this
+}
+
+var (
+	IteratorClass *Class
+	StopIteration *Class
+)
+
+func InitIterator() {
+	if IteratorClass != nil {
+		return
+	}
+
+	StopIteration = NewClass("StopIteration", ObjectClass)
+
+	IteratorClass = NewClass("Iterator", ObjectClass)
+	IteratorClass.AddGoMethod("has_next?", zeroArgs(iteratorHasNext))
+	IteratorClass.AddGoMethod("next", zeroArgs(iteratorNext))
+	IteratorClass.AddGoMethod("iterator", zeroArgs(iteratorSelf))
+
+	ArrayClass.AddGoMethod("iterator", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return newIterator(&arrayIterator{list: ARRAY(this)})
+	}))
+
+	RangeClass.AddGoMethod("iterator", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return newIterator(newRangeIterator(RANGE(this)))
+	}))
+
+	StringClass.AddGoMethod("iterator", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return newIterator(&stringIterator{value: unwrapString(this)})
+	}))
+
+	HashClass.AddGoMethod("iterator", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return newIterator(&hashIterator{current: HASH(this).head})
+	}))
+
+	TupleClass.AddGoMethod("iterator", zeroArgs(func(rt Runtime, this IrObject) IrObject {
+		return newIterator(&arrayIterator{list: NewArray(TUPLE(this).Elements)})
+	}))
+}
+
+type Iterator struct {
 	*base
+
+	source iterable
+	peeked IrObject
 }
 
-func (i *Iterator) HasNext() bool {
-	return i.index != i.list.Length()
+/*
+Returns True or False, or nil when the source failed. Finding
+out requires fetching the next element, which is kept until
+Next is called
+*/
+func (i *Iterator) HasNext(rt Runtime) IrObject {
+	if i.peeked == nil {
+		if i.peeked = i.source.next(rt); i.peeked == nil {
+			return nil
+		}
+	}
+
+	return Bool(i.peeked != StopIteration)
 }
 
-func (i *Iterator) Next() IrObject {
-	item := i.list.At(i.index)
-	i.index++
+/*
+Returns the next element, StopIteration once the
+iterator is exhausted or nil when the source failed
+*/
+func (i *Iterator) Next(rt Runtime) IrObject {
+	if item := i.peeked; item != nil {
+		if item != StopIteration {
+			i.peeked = nil
+		}
+
+		return item
+	}
+
+	item := i.source.next(rt)
+	if item == StopIteration {
+		i.peeked = item
+	}
+
 	return item
 }
 
-func NewIterator(obj IrObject) *Iterator {
+func newIterator(source iterable) *Iterator {
 	return &Iterator{
-		index: 0,
-		list:  ARRAY(obj),
-		base:  &base{class: NewClass("Iterator", ObjectClass)},
+		source: source,
+		base:   &base{class: IteratorClass},
 	}
 }
+
+/*
+Creates the iterator driving a for loop by calling the iterator
+method on obj, whatever it returns is expected to follow the
+iteration protocol
+*/
+func NewIterator(rt Runtime, obj IrObject) *Iterator {
+	if obj.Class().LookupMethod("iterator") == nil {
+		rt.SetError(NewTypeError("object is not iterable"))
+		return nil
+	}
+
+	result := call(rt, obj, "iterator")
+	if result == nil {
+		return nil
+	}
+
+	if iter, ok := result.(*Iterator); ok {
+		return iter
+	}
+
+	if result.Class().LookupMethod("next") == nil {
+		rt.SetError(NewTypeError("%s#iterator must return an object that responds to 'next'", obj.Class()))
+		return nil
+	}
+
+	return newIterator(&objectIterator{object: result})
+}
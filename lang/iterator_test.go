@@ -0,0 +1,141 @@
+package lang
+
+import (
+	"testing"
+)
+
+func collect(t *testing.T, obj IrObject) []IrObject {
+	t.Helper()
+
+	iter := NewIterator(globalTestDummyRuntime, obj)
+	if iter == nil {
+		t.Fatalf("unexpected error: %s", globalTestDummyRuntime.err)
+	}
+
+	var items []IrObject
+	for item := iter.Next(globalTestDummyRuntime); item != StopIteration; item = iter.Next(globalTestDummyRuntime) {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func Test_NewIterator(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Object   IrObject
+		Expected []IrObject
+	}{
+		{
+			Scenario: "array",
+			Object:   NewArray([]IrObject{Int(1), Int(2)}),
+			Expected: []IrObject{Int(1), Int(2)},
+		},
+		{
+			Scenario: "range",
+			Object:   newTestRange(t, 1, 3, true),
+			Expected: []IrObject{Int(1), Int(2)},
+		},
+		{
+			Scenario: "string",
+			Object:   NewString("ñu"),
+			Expected: []IrObject{NewString("ñ"), NewString("u")},
+		},
+		{
+			Scenario: "empty string",
+			Object:   NewString(""),
+			Expected: []IrObject{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			items := collect(t, test.Object)
+			if len(items) != len(test.Expected) {
+				t.Fatalf("expected %d items, got %d", len(test.Expected), len(items))
+			}
+
+			for i, expected := range test.Expected {
+				assertEqual(t, items[i], expected)
+			}
+		})
+	}
+}
+
+func Test_NewIterator_withHash(t *testing.T) {
+	h := NewHash()
+	hashInsert(globalTestDummyRuntime, h, NewString("a"), Int(1))
+
+	items := collect(t, h)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+
+	pair := ARRAY(items[0]).Elements
+	assertEqual(t, pair[0], NewString("a"))
+	assertEqual(t, pair[1], Int(1))
+}
+
+func Test_NewIterator_withHashChangedWhileIterating(t *testing.T) {
+	newHash := func() *Hash {
+		h := NewHash()
+		for i, key := range []string{"x", "y", "z"} {
+			hashInsert(globalTestDummyRuntime, h, NewString(key), Int(i))
+		}
+
+		return h
+	}
+
+	h := newHash()
+	iter := NewIterator(globalTestDummyRuntime, h)
+
+	first := iter.Next(globalTestDummyRuntime)
+	assertEqual(t, ARRAY(first).Elements[0], NewString("x"))
+
+	hashDelete(globalTestDummyRuntime, h, NewString("y"))
+	hashDelete(globalTestDummyRuntime, h, NewString("z"))
+	if next := iter.Next(globalTestDummyRuntime); next != StopIteration {
+		t.Errorf("expected deleted entries to be skipped, got %v", next)
+	}
+
+	h = newHash()
+	iter = NewIterator(globalTestDummyRuntime, h)
+	iter.Next(globalTestDummyRuntime)
+
+	hashDelete(globalTestDummyRuntime, h, NewString("y"))
+	assertEqual(t, ARRAY(iter.Next(globalTestDummyRuntime)).Elements[0], NewString("z"))
+
+	h = newHash()
+	iter = NewIterator(globalTestDummyRuntime, h)
+	iter.Next(globalTestDummyRuntime)
+
+	hashClear(globalTestDummyRuntime, h)
+	if next := iter.Next(globalTestDummyRuntime); next != StopIteration {
+		t.Errorf("expected a cleared hash to stop the iteration, got %v", next)
+	}
+}
+
+func Test_NewIterator_withNonIterable(t *testing.T) {
+	rt := new(dummyRuntime)
+
+	if iter := NewIterator(rt, Int(1)); iter != nil {
+		t.Fatalf("expected nil, got %v", iter)
+	}
+
+	if rt.err == nil || rt.err.message != "object is not iterable" {
+		t.Errorf("expected 'object is not iterable' error, got %v", rt.err)
+	}
+}
+
+func Test_Iterator_HasNext(t *testing.T) {
+	iter := NewIterator(globalTestDummyRuntime, NewArray([]IrObject{Int(1)}))
+
+	assertEqual(t, iteratorHasNext(globalTestDummyRuntime, iter), True)
+	assertEqual(t, iteratorHasNext(globalTestDummyRuntime, iter), True)
+	assertEqual(t, iteratorNext(globalTestDummyRuntime, iter), Int(1))
+	assertEqual(t, iteratorHasNext(globalTestDummyRuntime, iter), False)
+
+	if item := iteratorNext(globalTestDummyRuntime, iter); item != StopIteration {
+		t.Errorf("expected StopIteration, got %v", item)
+	}
+}
@@ -0,0 +1,154 @@
+package lang
+
+/*
+Maps the literal case values of a switch to the offsets of their
+bodies. Int keys spanning a small range are looked up in a dense
+slice, anything else goes through a map
+*/
+type JumpTable struct {
+	*base
+
+	keys    []IrObject
+	ints    map[Int]byte
+	strings map[string]byte
+	dense   []int
+	min     Int
+	Default byte
+}
+
+/*
+Adds key to the table, keys already in the table keep their
+first target since the first matching case wins
+*/
+func (t *JumpTable) Add(key IrObject, target byte) {
+	switch k := key.(type) {
+	case Int:
+		if _, ok := t.ints[k]; !ok {
+			t.ints[k] = target
+			t.keys = append(t.keys, key)
+		}
+	case *String:
+		if _, ok := t.strings[string(k.Value)]; !ok {
+			t.strings[string(k.Value)] = target
+			t.keys = append(t.keys, key)
+		}
+	}
+}
+
+/*
+Builds the dense slice when the Int keys cover
+at least half of the range between them
+*/
+func (t *JumpTable) Compact() {
+	if len(t.ints) == 0 {
+		return
+	}
+
+	first := true
+	var min, max Int
+	for k := range t.ints {
+		if first || k < min {
+			min = k
+		}
+
+		if first || k > max {
+			max = k
+		}
+
+		first = false
+	}
+
+	span := max - min + 1
+	if span <= 0 || span > 2*Int(len(t.ints)) {
+		return
+	}
+
+	t.min = min
+	t.dense = make([]int, span)
+	for i := range t.dense {
+		t.dense[i] = -1
+	}
+
+	for k, target := range t.ints {
+		t.dense[k-min] = int(target)
+	}
+}
+
+/*
+Returns the target for key, falling back to Default when no case
+matches. ok is false for keys that are neither Int nor String,
+whose == may still match one of the cases
+*/
+func (t *JumpTable) Lookup(key IrObject) (target byte, ok bool) {
+	switch k := key.(type) {
+	case Int:
+		if t.dense != nil {
+			if index := k - t.min; index >= 0 && index < Int(len(t.dense)) && t.dense[index] >= 0 {
+				return byte(t.dense[index]), true
+			}
+
+			return t.Default, true
+		}
+
+		if target, found := t.ints[k]; found {
+			return target, true
+		}
+
+		return t.Default, true
+
+	case *String:
+		if target, found := t.strings[string(k.Value)]; found {
+			return target, true
+		}
+
+		return t.Default, true
+	}
+
+	return 0, false
+}
+
+/*
+Returns the target for key like Lookup, keys that are neither Int
+nor String are compared with == against each case in order. ok is
+false when one of those calls fails
+*/
+func (t *JumpTable) Match(rt Runtime, key IrObject) (target byte, ok bool) {
+	if target, ok := t.Lookup(key); ok {
+		return target, true
+	}
+
+	for _, k := range t.keys {
+		equal := call(rt, key, "==", k)
+		if equal == nil {
+			return 0, false
+		}
+
+		if IsTruthy(equal) {
+			target, _ := t.Lookup(k)
+			return target, true
+		}
+	}
+
+	return t.Default, true
+}
+
+/*
+Calls fn for each key in the order they were added
+*/
+func (t *JumpTable) Each(fn func(key IrObject, target byte)) {
+	for _, key := range t.keys {
+		target, _ := t.Lookup(key)
+		fn(key, target)
+	}
+}
+
+func (t *JumpTable) Dense() bool {
+	return t.dense != nil
+}
+
+func NewJumpTable() *JumpTable {
+	return &JumpTable{
+		ints:    make(map[Int]byte),
+		strings: make(map[string]byte),
+	}
+}
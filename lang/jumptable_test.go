@@ -0,0 +1,71 @@
+package lang
+
+import (
+	"testing"
+)
+
+func Test_JumpTable_Lookup(t *testing.T) {
+	tests := []struct {
+		Scenario string
+		Keys     []IrObject
+		Dense    bool
+	}{
+		{
+			Scenario: "dense ints",
+			Keys:     []IrObject{Int(1), Int(2), Int(4)},
+			Dense:    true,
+		},
+		{
+			Scenario: "sparse ints",
+			Keys:     []IrObject{Int(1), Int(100), Int(-100)},
+			Dense:    false,
+		},
+		{
+			Scenario: "strings",
+			Keys:     []IrObject{NewString("a"), NewString("b")},
+			Dense:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			table := NewJumpTable()
+			table.Default = 99
+			for i, key := range test.Keys {
+				table.Add(key, byte(i))
+			}
+			table.Compact()
+
+			if table.Dense() != test.Dense {
+				t.Errorf("expected dense to be %t, got %t", test.Dense, table.Dense())
+			}
+
+			for i, key := range test.Keys {
+				if target, ok := table.Lookup(key); !ok || target != byte(i) {
+					t.Errorf("expected %s to jump to %d, got %d", key, i, target)
+				}
+			}
+
+			for _, key := range []IrObject{Int(3), Int(1000), NewString("z")} {
+				if target, ok := table.Lookup(key); !ok || target != table.Default {
+					t.Errorf("expected %s to jump to default, got %d", key, target)
+				}
+			}
+
+			if _, ok := table.Lookup(Float(1)); ok {
+				t.Errorf("expected Float key to not be looked up")
+			}
+		})
+	}
+}
+
+func Test_JumpTable_AddKeepsFirstTarget(t *testing.T) {
+	table := NewJumpTable()
+	table.Add(Int(1), 2)
+	table.Add(Int(1), 4)
+	table.Compact()
+
+	if target, _ := table.Lookup(Int(1)); target != 2 {
+		t.Errorf("expected 1 to jump to 2, got %d", target)
+	}
+}
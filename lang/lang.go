@@ -1,35 +1,51 @@
 package lang
 
-var classes map[string]*Class
+// the builtin classes, shared by every Namespace
+var constants map[string]IrObject
 
 func init() {
 	InitClass()
 	InitObject()
 	InitError()
 	InitString()
+	InitSymbol()
 	InitFloat()
 	InitInt()
 	InitNone()
 	InitBool()
 	InitHash()
 	InitArray()
+	InitRange()
+	InitTuple()
+	InitEnum()
+	InitRecord()
+	InitIterator()
 	InitScript()
 
-	classes = map[string]*Class{
+	constants = map[string]IrObject{
 		"Object":  ObjectClass,
 		"Int":     IntClass,
 		"Float":   FloatClass,
 		"String":  StringClass,
+		"Symbol":  SymbolClass,
 		"None":    NoneClass,
 		"Boolean": BoolClass,
 		"Hash":    HashClass,
 		"Array":   ArrayClass,
+		"Range":   RangeClass,
+		"Tuple":   TupleClass,
+		"Enum":    EnumClass,
+		"Record":  RecordClass,
+
+		"Iterator":      IteratorClass,
+		"StopIteration": StopIteration,
 
 		"Error":             Error,
 		"NameError":         NameError,
 		"TypeError":         TypeError,
 		"RuntimeError":      RuntimeError,
 		"ArgumentError":     ArgumentError,
+		"KeyError":          KeyError,
 		"NoMethodError":     NoMethodError,
 		"ZeroDivisionError": ZeroDivisionError,
 	}
@@ -48,12 +64,55 @@ func IsTruthy(obj IrObject) Bool {
 }
 
 func TypeLookup(name IrObject) *Class {
+	class, _ := ConstantLookup(name).(*Class)
+	return class
+}
+
+func ConstantLookup(name IrObject) IrObject {
 	n := unwrapString(name)
-	return classes[string(n)]
+	return constants[string(n)]
+}
+
+/*
+The constants and types a program defines, they
+are looked up before the builtin classes
+*/
+type Namespace struct {
+	defined map[string]IrObject
+}
+
+func NewNamespace() *Namespace {
+	return &Namespace{defined: make(map[string]IrObject)}
+}
+
+func (n *Namespace) Lookup(name IrObject) IrObject {
+	if value, ok := n.defined[string(unwrapString(name))]; ok {
+		return value
+	}
+
+	return ConstantLookup(name)
 }
 
-func DefineType(name string, class *Class) {
-	classes[name] = class
+func (n *Namespace) LookupType(name IrObject) *Class {
+	class, _ := n.Lookup(name).(*Class)
+	return class
+}
+
+func (n *Namespace) DefineType(name string, class *Class) {
+	n.defined[name] = class
+}
+
+/*
+Defines a top-level constant, reporting false when
+the name is already taken
+*/
+func (n *Namespace) DefineConstant(name string, value IrObject) bool {
+	if n.Lookup(NewString(name)) != nil {
+		return false
+	}
+
+	n.defined[name] = value
+	return true
 }
 
 type IrObject interface {
@@ -64,6 +123,7 @@ type IrObject interface {
 type Runtime interface {
 	SetError(*ErrorObject)
 	Call(IrObject, *Method, ...IrObject) IrObject
+	CallKeywords(IrObject, *Method, []string, []IrObject, ...IrObject) IrObject
 }
 
 func NewScript() IrObject {
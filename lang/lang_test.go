@@ -12,6 +12,9 @@ func (rt *dummyRuntime) SetError(err *ErrorObject) { rt.err = err }
 func (rt *dummyRuntime) Call(recv IrObject, method *Method, args ...IrObject) IrObject {
 	return method.Native().Invoke(rt, recv, args...)
 }
+func (rt *dummyRuntime) CallKeywords(recv IrObject, method *Method, names []string, values []IrObject, args ...IrObject) IrObject {
+	return InvokeNative(rt, method.Native(), recv, names, values, args...)
+}
 
 var globalTestDummyRuntime = new(dummyRuntime)
 
@@ -37,3 +40,59 @@ func assertEqual(t *testing.T, got IrObject, expected IrObject) {
 		t.Errorf("expected value to be %v, got %v", expected, got)
 	}
 }
+
+func Test_DefineConstant(t *testing.T) {
+	namespace := NewNamespace()
+	if !namespace.DefineConstant("LIMIT", Int(3)) {
+		t.Fatal("expected LIMIT to be defined")
+	}
+
+	assertEqual(t, namespace.Lookup(NewString("LIMIT")), Int(3))
+
+	if namespace.LookupType(NewString("LIMIT")) != nil {
+		t.Error("expected a constant not to be a type")
+	}
+
+	if namespace.DefineConstant("LIMIT", Int(4)) || namespace.DefineConstant("Int", Int(1)) {
+		t.Error("expected LIMIT and Int not to be redefined")
+	}
+
+	if ConstantLookup(NewString("LIMIT")) != nil || NewNamespace().Lookup(NewString("LIMIT")) != nil {
+		t.Error("expected LIMIT to only be defined in its namespace")
+	}
+
+	if NewNamespace().LookupType(NewString("Int")) != IntClass {
+		t.Error("expected the builtin classes in every namespace")
+	}
+}
+
+func Test_DefineEnum(t *testing.T) {
+	namespace := NewNamespace()
+	enum := NewEnum("Color", []string{"Red", "Green"}, []IrObject{None, Int(2)})
+	if !namespace.DefineEnum(enum) {
+		t.Fatal("expected Color to be defined")
+	}
+
+	green := namespace.Lookup(NewString("Color.Green"))
+	assertEqual(t, enumName(globalTestDummyRuntime, green), NewString("Green"))
+	assertEqual(t, enumOrdinal(globalTestDummyRuntime, green), Int(1))
+	assertEqual(t, enumValue(globalTestDummyRuntime, green), Int(2))
+	assertEqual(t, enumInspect(globalTestDummyRuntime, green), NewString("Color.Green"))
+
+	if !green.Is(enum) || !green.Is(EnumClass) {
+		t.Error("expected a member to be an instance of its enum")
+	}
+
+	values := ARRAY(enumValues(globalTestDummyRuntime, enum))
+	if len(values.Elements) != 2 || values.Elements[1] != green {
+		t.Errorf("expected values to list the members, got %v", values.Elements)
+	}
+
+	if enum.Class().LookupMethod("values") == nil || ObjectClass.Class().LookupMethod("values") != nil {
+		t.Error("expected only enums to respond to values")
+	}
+
+	if namespace.DefineEnum(enum) {
+		t.Error("expected Color not to be redefined")
+	}
+}
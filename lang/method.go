@@ -7,6 +7,12 @@ const (
 	IrMethod
 )
 
+// A parameter passed by name
+type KeywordParam struct {
+	Name     string
+	Required bool
+}
+
 type Method struct {
 	*base
 
@@ -14,6 +20,8 @@ type Method struct {
 	name        string
 	arity       byte
 	optArgc     byte
+	rest        bool
+	keywords    []KeywordParam
 	body        any
 	localCount  byte
 	constants   []IrObject
@@ -29,7 +37,71 @@ func (m *Method) Constants() []IrObject  { return m.constants }
 func (m *Method) LocalCount() byte       { return m.localCount }
 func (m *Method) CatchOffset() int       { return m.catchOffset }
 
+/*
+Reports whether the last parameter collects
+the remaining arguments into an Array
+*/
+func (m *Method) Rest() bool { return m.rest }
+
+func (m *Method) Keywords() []KeywordParam { return m.keywords }
+
+/*
+Orders the keyword arguments of a call as the keyword parameters
+are declared, None standing for the ones not given. Unknown
+keywords and missing required ones are an ArgumentError
+*/
+func (m *Method) BindKeywords(names []string, values []IrObject) ([]IrObject, *ErrorObject) {
+	if len(m.keywords) == 0 {
+		if len(names) != 0 {
+			return nil, NewError("unknown keyword: %s", ArgumentError, names[0])
+		}
+
+		return nil, nil
+	}
+
+	bound := make([]IrObject, len(m.keywords))
+	for i, name := range names {
+		index := -1
+		for j, keyword := range m.keywords {
+			if keyword.Name == name {
+				index = j
+				break
+			}
+		}
+
+		if index < 0 {
+			return nil, NewError("unknown keyword: %s", ArgumentError, name)
+		}
+
+		bound[index] = values[i]
+	}
+
+	for i, keyword := range m.keywords {
+		if bound[i] != nil {
+			continue
+		}
+
+		if keyword.Required {
+			return nil, NewError("missing keyword: %s", ArgumentError, keyword.Name)
+		}
+
+		bound[i] = None
+	}
+
+	return bound, nil
+}
+
 func (m *Method) CheckArity(given byte) *ErrorObject {
+	if m.rest {
+		min := m.arity - 1 - m.optArgc
+		if given < min {
+			format := "wrong number of arguments (given %d, expected %d+)"
+			return NewError(format, ArgumentError, given, min)
+		}
+
+		return nil
+	}
+
 	if m.optArgc == 0 && given != m.arity {
 		return NewArityError(int(given), int(m.arity))
 	}
@@ -52,12 +124,14 @@ func NewGoMethod(name string, body Native, arity byte) *Method {
 	}
 }
 
-func NewIrMethod(name string, arity byte, optArgc byte, body []uint16, localCount byte, consts []IrObject, catchOffset int) *Method {
+func NewIrMethod(name string, arity byte, optArgc byte, rest bool, keywords []KeywordParam, body []uint16, localCount byte, consts []IrObject, catchOffset int) *Method {
 	return &Method{
 		methodType:  IrMethod,
 		name:        name,
 		arity:       arity,
 		optArgc:     optArgc,
+		rest:        rest,
+		keywords:    keywords,
 		body:        body,
 		localCount:  localCount,
 		constants:   consts,
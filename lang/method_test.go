@@ -0,0 +1,90 @@
+package lang
+
+import "testing"
+
+func TestMethod_CheckArity(t *testing.T) {
+	table := []struct {
+		scenario string
+		method   *Method
+		given    byte
+		wantErr  string
+	}{
+		{
+			scenario: "exact",
+			method:   NewIrMethod("f", 2, 0, false, nil, nil, 2, nil, -1),
+			given:    1,
+			wantErr:  "wrong number of arguments (given 1, expected 2)",
+		},
+		{
+			scenario: "optional",
+			method:   NewIrMethod("f", 2, 1, false, nil, nil, 2, nil, -1),
+			given:    3,
+			wantErr:  "wrong number of arguments (given 3, expected 1..2)",
+		},
+		{
+			scenario: "rest without extra arguments",
+			method:   NewIrMethod("f", 2, 0, true, nil, nil, 2, nil, -1),
+			given:    1,
+		},
+		{
+			scenario: "rest with extra arguments",
+			method:   NewIrMethod("f", 2, 0, true, nil, nil, 2, nil, -1),
+			given:    5,
+		},
+		{
+			scenario: "rest with missing arguments",
+			method:   NewIrMethod("f", 3, 0, true, nil, nil, 3, nil, -1),
+			given:    1,
+			wantErr:  "wrong number of arguments (given 1, expected 2+)",
+		},
+		{
+			scenario: "rest after optional",
+			method:   NewIrMethod("f", 3, 1, true, nil, nil, 3, nil, -1),
+			given:    1,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := test.method.CheckArity(test.given)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err.message)
+				}
+				return
+			}
+
+			if err == nil || err.message != test.wantErr {
+				t.Errorf("expected error %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMethod_BindKeywords(t *testing.T) {
+	keywords := []KeywordParam{{Name: "port"}, {Name: "host", Required: true}}
+	method := NewIrMethod("f", 0, 0, false, keywords, nil, 2, nil, -1)
+
+	bound, err := method.BindKeywords([]string{"host"}, []IrObject{NewString("h")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.message)
+	}
+
+	if bound[0] != None {
+		t.Errorf("expected port to be None, got %v", bound[0])
+	}
+	assertEqual(t, bound[1], NewString("h"))
+
+	if _, err := method.BindKeywords([]string{"tls"}, []IrObject{True}); err == nil || err.message != "unknown keyword: tls" {
+		t.Errorf("expected unknown keyword error, got %v", err)
+	}
+
+	if _, err := method.BindKeywords(nil, nil); err == nil || err.message != "missing keyword: host" {
+		t.Errorf("expected missing keyword error, got %v", err)
+	}
+
+	plain := NewIrMethod("g", 0, 0, false, nil, nil, 0, nil, -1)
+	if _, err := plain.BindKeywords([]string{"port"}, []IrObject{Int(1)}); err == nil || err.message != "unknown keyword: port" {
+		t.Errorf("expected unknown keyword error, got %v", err)
+	}
+}
@@ -9,6 +9,10 @@ func returnFalse(rt Runtime, this IrObject) IrObject {
 	return False
 }
 
+func returnThis(rt Runtime, this IrObject) IrObject {
+	return this
+}
+
 func objectPuts(rt Runtime, this IrObject, args ...IrObject) IrObject {
 	for _, arg := range args {
 		v := call(rt, arg, "to_str")
@@ -40,6 +44,20 @@ func objectNotEqual(rt Runtime, this IrObject, other IrObject) IrObject {
 	return !IsTruthy(result)
 }
 
+func objectCaseEqual(rt Runtime, this IrObject, other IrObject) IrObject {
+	return call(rt, this, "==", other)
+}
+
+func objectIsA(rt Runtime, this IrObject, class IrObject) IrObject {
+	c, ok := class.(*Class)
+	if !ok {
+		rt.SetError(NewTypeError("class required, got %s", class.Class()))
+		return nil
+	}
+
+	return Bool(this.Is(c))
+}
+
 func objectInspect(rt Runtime, this IrObject) IrObject {
 	id := objectId(rt, this)
 	str := fmt.Sprintf("#<%s:0x%x>", this.Class(), INT(id))
@@ -75,6 +93,21 @@ func NewObject() *Object {
 	}
 }
 
+/*
+Reports whether collection holds element. Collections
+answer through include?, or contains when they lack it
+*/
+func Contains(rt Runtime, element, collection IrObject) IrObject {
+	for _, name := range []string{"include?", "contains"} {
+		if collection.Class().LookupMethod(name) != nil {
+			return call(rt, collection, name, element)
+		}
+	}
+
+	rt.SetError(NewTypeError("%s does not respond to include? or contains", collection.Class()))
+	return nil
+}
+
 var ObjectClass *Class
 
 func InitObject() {
@@ -94,6 +127,8 @@ func InitObject() {
 	ObjectClass.AddGoMethod("init", zeroArgs(objectInit))
 	ObjectClass.AddGoMethod("==", oneArg(objectEqual))
 	ObjectClass.AddGoMethod("!=", oneArg(objectNotEqual))
+	ObjectClass.AddGoMethod("===", oneArg(objectCaseEqual))
+	ObjectClass.AddGoMethod("is_a?", oneArg(objectIsA))
 	ObjectClass.AddGoMethod("hash", zeroArgs(objectId))
 	ObjectClass.AddGoMethod("puts", nArgs(objectPuts))
 	ObjectClass.AddGoMethod("object_id", zeroArgs(objectId))
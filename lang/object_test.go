@@ -60,3 +60,74 @@ func Test_objectNotEqual(t *testing.T) {
 		})
 	}
 }
+
+func Test_caseEqual(t *testing.T) {
+	table := []struct {
+		scenario   string
+		pattern    IrObject
+		value      IrObject
+		wantOutput Bool
+	}{
+		{scenario: "Int/Equal", pattern: Int(1), value: Int(1), wantOutput: True},
+		{scenario: "Int/NotEqual", pattern: Int(1), value: NewString("1"), wantOutput: False},
+		{scenario: "Class/Instance", pattern: IntClass, value: Int(1), wantOutput: True},
+		{scenario: "Class/Subclass", pattern: Error, value: NewTypeError("boom"), wantOutput: True},
+		{scenario: "Class/Other", pattern: StringClass, value: Int(1), wantOutput: False},
+		{scenario: "Range/Included", pattern: newTestRange(t, 1, 5, false), value: Int(5), wantOutput: True},
+		{scenario: "Range/Excluded", pattern: newTestRange(t, 1, 5, true), value: Int(5), wantOutput: False},
+	}
+
+	for _, test := range table {
+		t.Run(test.scenario, func(t *testing.T) {
+			result := call(globalTestDummyRuntime, test.pattern, "===", test.value)
+			assertEqual(t, result, test.wantOutput)
+		})
+	}
+}
+
+func Test_objectIsA(t *testing.T) {
+	assertEqual(t, objectIsA(globalTestDummyRuntime, NewTypeError("boom"), RuntimeError), True)
+	assertEqual(t, objectIsA(globalTestDummyRuntime, Int(1), FloatClass), False)
+
+	rt := new(dummyRuntime)
+	if result := objectIsA(rt, Int(1), Int(1)); result != nil || rt.err == nil {
+		t.Errorf("expected TypeError, got %v", result)
+	}
+}
+
+func Test_Contains(t *testing.T) {
+	hash := NewHash()
+	hashInsert(globalTestDummyRuntime, hash, NewString("a"), Int(1))
+	rng, _ := NewRange(Int(1), Int(3), false)
+
+	table := []struct {
+		scenario   string
+		element    IrObject
+		collection IrObject
+		wantOutput IrObject
+	}{
+		{scenario: "Array/Included", element: Int(2), collection: NewArray([]IrObject{Int(1), Int(2)}), wantOutput: True},
+		{scenario: "Array/Missing", element: Int(3), collection: NewArray([]IrObject{Int(1), Int(2)}), wantOutput: False},
+		{scenario: "Hash/Key", element: NewString("a"), collection: hash, wantOutput: True},
+		{scenario: "Hash/Missing", element: NewString("b"), collection: hash, wantOutput: False},
+		{scenario: "String/Substring", element: NewString("ll"), collection: NewString("hello"), wantOutput: True},
+		{scenario: "Range/Included", element: Int(3), collection: rng, wantOutput: True},
+		{scenario: "Range/Missing", element: Int(4), collection: rng, wantOutput: False},
+	}
+
+	for _, test := range table {
+		t.Run(test.scenario, func(t *testing.T) {
+			result := Contains(globalTestDummyRuntime, test.element, test.collection)
+			assertEqual(t, result, test.wantOutput)
+		})
+	}
+
+	rt := new(dummyRuntime)
+	if result := Contains(rt, Int(1), Int(2)); result != nil {
+		t.Fatalf("expected nil, got %v", result)
+	}
+
+	if rt.err == nil || rt.err.message != "Int does not respond to include? or contains" {
+		t.Errorf("expected TypeError, got %v", rt.err)
+	}
+}
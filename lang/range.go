@@ -0,0 +1,289 @@
+package lang
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// the most elements a range converts into an array
+const maxRangeArray = 1 << 26
+
+func RANGE(obj IrObject) *Range {
+	return obj.(*Range)
+}
+
+/*
+Resolves the range against a sequence of the given size,
+negative bounds count from the end of the sequence
+*/
+func rangeBounds(r *Range, size int) (int, int, bool) {
+	start, end := int(r.Start), int(r.End)
+
+	if start < 0 {
+		start += size
+	}
+
+	if end < 0 {
+		end += size
+	}
+
+	// an inclusive end past the sequence is clamped before the increment can overflow
+	if end >= size {
+		end = size
+	} else if !r.Exclusive {
+		end++
+	}
+
+	if start < 0 || start > size {
+		return 0, 0, false
+	}
+
+	if end > size {
+		end = size
+	}
+
+	if end < start {
+		end = start
+	}
+
+	return start, end, true
+}
+
+/*
+Calls fn with the indexes from start to end stepping by the
+step of the range, stopping before the step overflows
+*/
+func eachIndex(r *Range, start, end int, fn func(int)) {
+	step := int(r.Step)
+	for i := start; i < end; i += step {
+		fn(i)
+
+		if end-i <= step {
+			return
+		}
+	}
+}
+
+func rangeSize(rt Runtime, this IrObject) IrObject {
+	steps, ok := RANGE(this).steps()
+	if !ok {
+		return Int(0)
+	}
+
+	// the bounds of an Int range can be up to 2^64 elements apart
+	size := new(big.Int).SetUint64(steps)
+	return NewInteger(size.Add(size, big.NewInt(1)))
+}
+
+func rangeInclude(rt Runtime, this, value IrObject) IrObject {
+	r := RANGE(this)
+
+	switch v := value.(type) {
+	case Int:
+		return Bool(r.contains(v))
+	case Float:
+		if r.Step != 1 {
+			return Bool(v == Float(Int(v)) && r.contains(Int(v)))
+		}
+
+		if v < Float(r.Start) {
+			return False
+		}
+
+		if r.Exclusive {
+			return Bool(v < Float(r.End))
+		}
+
+		return Bool(v <= Float(r.End))
+	default:
+		return False
+	}
+}
+
+func rangeStep(rt Runtime, this, step IrObject) IrObject {
+	n, err := toInt(step)
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
+	if n <= 0 {
+		rt.SetError(NewError("step can't be negative or zero", ArgumentError))
+		return nil
+	}
+
+	r := RANGE(this)
+	if n > math.MaxInt64/r.Step {
+		rt.SetError(NewError("step %d is too large", ArgumentError, n))
+		return nil
+	}
+
+	return &Range{
+		Start:     r.Start,
+		End:       r.End,
+		Step:      r.Step * n,
+		Exclusive: r.Exclusive,
+		base:      &base{class: RangeClass},
+	}
+}
+
+func rangeToArray(rt Runtime, this IrObject) IrObject {
+	r := RANGE(this)
+	if steps, ok := r.steps(); ok && steps >= maxRangeArray {
+		rt.SetError(NewError("range %s is too large to convert into an Array", ArgumentError, r))
+		return nil
+	}
+
+	var elements []IrObject
+	for it := newRangeIterator(r); it.hasNext(); {
+		elements = append(elements, it.next(rt))
+	}
+
+	return NewArray(elements)
+}
+
+func rangeFirst(rt Runtime, this IrObject) IrObject {
+	r := RANGE(this)
+	if _, ok := r.steps(); !ok {
+		return None
+	}
+
+	return r.Start
+}
+
+func rangeLast(rt Runtime, this IrObject) IrObject {
+	r := RANGE(this)
+
+	steps, ok := r.steps()
+	if !ok {
+		return None
+	}
+
+	return r.last(steps)
+}
+
+func rangeEqual(rt Runtime, this, other IrObject) IrObject {
+	y, ok := other.(*Range)
+	if !ok {
+		return False
+	}
+
+	x := RANGE(this)
+	return Bool(x.Start == y.Start && x.End == y.End && x.Step == y.Step && x.Exclusive == y.Exclusive)
+}
+
+func rangeHash(rt Runtime, this IrObject) IrObject {
+	r := RANGE(this)
+
+	hash := 31*r.Start + r.End
+	hash = 31*hash + r.Step
+	if r.Exclusive {
+		hash = 31 * hash
+	}
+
+	return hash
+}
+
+func rangeInspect(rt Runtime, this IrObject) IrObject {
+	return NewString(RANGE(this).String())
+}
+
+var RangeClass *Class
+
+func InitRange() {
+	if RangeClass != nil {
+		return
+	}
+
+	RangeClass = NewClass("Range", ObjectClass)
+	RangeClass.AddGoMethod("==", oneArg(rangeEqual))
+	RangeClass.AddGoMethod("hash", zeroArgs(rangeHash))
+	RangeClass.AddGoMethod("size", zeroArgs(rangeSize))
+	RangeClass.AddGoMethod("include?", oneArg(rangeInclude))
+	RangeClass.AddGoMethod("===", oneArg(rangeInclude))
+	RangeClass.AddGoMethod("step", oneArg(rangeStep))
+	RangeClass.AddGoMethod("to_a", zeroArgs(rangeToArray))
+	RangeClass.AddGoMethod("first", zeroArgs(rangeFirst))
+	RangeClass.AddGoMethod("last", zeroArgs(rangeLast))
+	RangeClass.AddGoMethod("inspect", zeroArgs(rangeInspect))
+	RangeClass.AddGoMethod("to_str", zeroArgs(rangeInspect))
+}
+
+/*
+Represents a sequence of integers between two bounds, the upper
+bound is left out when the range is exclusive
+*/
+type Range struct {
+	*base
+
+	Start     Int
+	End       Int
+	Step      Int
+	Exclusive bool
+}
+
+/*
+Returns the number of steps from the start to the last
+element, ok is false when the range is empty. Unsigned
+math keeps the span of any two bounds from overflowing
+*/
+func (r *Range) steps() (steps uint64, ok bool) {
+	end := r.End
+	if r.Exclusive {
+		if end == math.MinInt64 {
+			return 0, false
+		}
+
+		end--
+	}
+
+	if end < r.Start {
+		return 0, false
+	}
+
+	return (uint64(end) - uint64(r.Start)) / uint64(r.Step), true
+}
+
+// the result wraps back into Int since the last element is one
+func (r *Range) last(steps uint64) Int {
+	return Int(uint64(r.Start) + steps*uint64(r.Step))
+}
+
+func (r *Range) contains(value Int) bool {
+	if value < r.Start || value > r.End || (r.Exclusive && value == r.End) {
+		return false
+	}
+
+	return (value-r.Start)%r.Step == 0
+}
+
+func (r *Range) String() string {
+	operator := ".."
+	if r.Exclusive {
+		operator = "..."
+	}
+
+	if r.Step != 1 {
+		return fmt.Sprintf("(%d%s%d).step(%d)", r.Start, operator, r.End, r.Step)
+	}
+
+	return fmt.Sprintf("%d%s%d", r.Start, operator, r.End)
+}
+
+func NewRange(start, end IrObject, exclusive bool) (*Range, *ErrorObject) {
+	first, isInt := start.(Int)
+	last, bothInt := end.(Int)
+
+	if !isInt || !bothInt {
+		return nil, NewTypeError("bad value for range: '%s' and '%s'", start.Class(), end.Class())
+	}
+
+	return &Range{
+		Start:     first,
+		End:       last,
+		Step:      1,
+		Exclusive: exclusive,
+		base:      &base{class: RangeClass},
+	}, nil
+}
@@ -0,0 +1,165 @@
+package lang
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestRange(t *testing.T, start, end Int, exclusive bool) *Range {
+	t.Helper()
+
+	r, err := NewRange(start, end, exclusive)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return r
+}
+
+func Test_NewRange_withNonIntBounds(t *testing.T) {
+	_, err := NewRange(Int(1), NewString("a"), false)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	if err.message != "bad value for range: 'Int' and 'String'" {
+		t.Errorf("unexpected error message: %s", err.message)
+	}
+}
+
+func Test_rangeSize(t *testing.T) {
+	tests := []struct {
+		Range    *Range
+		Expected Int
+	}{
+		{Range: newTestRange(t, 1, 10, false), Expected: 10},
+		{Range: newTestRange(t, 1, 10, true), Expected: 9},
+		{Range: newTestRange(t, 5, 1, false), Expected: 0},
+		{Range: newTestRange(t, 3, 3, true), Expected: 0},
+		{Range: newTestRange(t, 0, 9000000000000000000, false), Expected: 9000000000000000001},
+		{Range: newTestRange(t, math.MinInt64, math.MinInt64, true), Expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Range.String(), func(t *testing.T) {
+			assertEqual(t, rangeSize(globalTestDummyRuntime, test.Range), test.Expected)
+		})
+	}
+}
+
+func Test_rangeInclude(t *testing.T) {
+	inclusive := newTestRange(t, 1, 10, false)
+	exclusive := newTestRange(t, 1, 10, true)
+
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, inclusive, Int(10)), True)
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, exclusive, Int(10)), False)
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, inclusive, Int(0)), False)
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, inclusive, Float(9.5)), True)
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, inclusive, NewString("1")), False)
+}
+
+func Test_rangeStep(t *testing.T) {
+	r := rangeStep(globalTestDummyRuntime, newTestRange(t, 0, 10, true), Int(2))
+
+	assertEqual(t, rangeInspect(globalTestDummyRuntime, r), NewString("(0...10).step(2)"))
+	assertEqual(t, rangeSize(globalTestDummyRuntime, r), Int(5))
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, r, Int(4)), True)
+	assertEqual(t, rangeInclude(globalTestDummyRuntime, r, Int(5)), False)
+	assertEqual(t, rangeLast(globalTestDummyRuntime, r), Int(8))
+
+	elements := ARRAY(rangeToArray(globalTestDummyRuntime, r)).Elements
+	for i, expected := range []Int{0, 2, 4, 6, 8} {
+		assertEqual(t, elements[i], expected)
+	}
+}
+
+func Test_rangeSize_pastInt(t *testing.T) {
+	size := rangeSize(globalTestDummyRuntime, newTestRange(t, -9000000000000000000, 9000000000000000000, false))
+	if big, ok := size.(*BigInt); !ok || big.String() != "18000000000000000001" {
+		t.Errorf("expected the size to be 18000000000000000001, got %v", size)
+	}
+
+	count := 0
+	it := newRangeIterator(newTestRange(t, math.MaxInt64-2, math.MaxInt64, false))
+	for it.hasNext() {
+		it.next(globalTestDummyRuntime)
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("expected 3 elements up to MaxInt64, got %d", count)
+	}
+}
+
+func Test_rangeToArray_tooLarge(t *testing.T) {
+	rt := new(dummyRuntime)
+	if result := rangeToArray(rt, newTestRange(t, 0, 9000000000000000000, false)); result != nil || rt.err == nil {
+		t.Errorf("expected a too large error, got %v", result)
+	}
+}
+
+func Test_rangeStep_withInvalidStep(t *testing.T) {
+	rt := new(dummyRuntime)
+
+	rangeStep(rt, newTestRange(t, 0, 10, false), Int(0))
+	if rt.err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	if rt.err.message != "step can't be negative or zero" {
+		t.Errorf("unexpected error message: %s", rt.err.message)
+	}
+
+	rt = new(dummyRuntime)
+	twice := rangeStep(rt, newTestRange(t, 0, 10, false), Int(2))
+	if result := rangeStep(rt, twice, Int(math.MaxInt64)); result != nil || rt.err == nil {
+		t.Errorf("expected an overflowing step to fail, got %v", result)
+	}
+}
+
+func Test_arrayAt_withRange(t *testing.T) {
+	array := NewArray([]IrObject{Int(1), Int(2), Int(3), Int(4), Int(5)})
+
+	tests := []struct {
+		Range    *Range
+		Expected []Int
+	}{
+		{Range: newTestRange(t, 1, 3, false), Expected: []Int{2, 3, 4}},
+		{Range: newTestRange(t, 1, 3, true), Expected: []Int{2, 3}},
+		{Range: newTestRange(t, -2, -1, false), Expected: []Int{4, 5}},
+		{Range: newTestRange(t, 3, 100, false), Expected: []Int{4, 5}},
+		{Range: newTestRange(t, 0, math.MaxInt64, false), Expected: []Int{1, 2, 3, 4, 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Range.String(), func(t *testing.T) {
+			elements := ARRAY(arrayAt(globalTestDummyRuntime, array, test.Range)).Elements
+			if len(elements) != len(test.Expected) {
+				t.Fatalf("expected %d elements, got %d", len(test.Expected), len(elements))
+			}
+
+			for i, expected := range test.Expected {
+				assertEqual(t, elements[i], expected)
+			}
+		})
+	}
+
+	if value := arrayAt(globalTestDummyRuntime, array, newTestRange(t, 10, 12, false)); value != None {
+		t.Errorf("expected None, got %v", value)
+	}
+}
+
+func Test_stringAt(t *testing.T) {
+	str := NewString("iracema")
+
+	assertEqual(t, stringAt(globalTestDummyRuntime, str, Int(0)), NewString("i"))
+	assertEqual(t, stringAt(globalTestDummyRuntime, str, Int(-1)), NewString("a"))
+	assertEqual(t, stringAt(globalTestDummyRuntime, str, newTestRange(t, 0, 2, false)), NewString("ira"))
+	assertEqual(t, stringAt(globalTestDummyRuntime, str, newTestRange(t, 2, -1, true)), NewString("acem"))
+	assertEqual(t, stringAt(globalTestDummyRuntime, str, newTestRange(t, 4, math.MaxInt64, false)), NewString("ema"))
+
+	accented := NewString("héy")
+	assertEqual(t, stringAt(globalTestDummyRuntime, accented, Int(1)), NewString("é"))
+	assertEqual(t, stringAt(globalTestDummyRuntime, accented, Int(-1)), NewString("y"))
+	assertEqual(t, stringAt(globalTestDummyRuntime, accented, newTestRange(t, 1, 2, false)), NewString("éy"))
+}
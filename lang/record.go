@@ -0,0 +1,115 @@
+package lang
+
+import "strings"
+
+/*
+Two records are equal when they are instances of the same
+record and their fields hold equal values
+*/
+func recordEqual(rt Runtime, this IrObject, other IrObject) IrObject {
+	y, ok := other.(*Object)
+	if !ok || y.class != this.Class() {
+		return False
+	}
+
+	for i, value := range this.(*Object).values {
+		equal := call(rt, value, "==", y.values[i])
+		if equal == nil {
+			return nil
+		}
+
+		if !IsTruthy(equal) {
+			return False
+		}
+	}
+
+	return True
+}
+
+/*
+Combines the hashes of the fields, so records equal
+by recordEqual land in the same bucket of a Hash
+*/
+func recordHash(rt Runtime, this IrObject) IrObject {
+	hash := Int(1)
+	for _, value := range this.(*Object).values {
+		code, ok := callHash(rt, value)
+		if !ok {
+			return nil
+		}
+
+		hash = hash*31 + code
+	}
+
+	return hash
+}
+
+func recordInspect(rt Runtime, this IrObject) IrObject {
+	object := this.(*Object)
+
+	var buf strings.Builder
+	buf.WriteString(object.class.name)
+	buf.WriteByte('(')
+	for i, name := range object.class.fieldNames() {
+		val := call(rt, object.values[i], "inspect")
+		if val == nil {
+			return nil
+		}
+
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(name)
+		buf.WriteString(": ")
+		buf.Write(unwrapString(val))
+	}
+	buf.WriteByte(')')
+
+	return NewString(buf.String())
+}
+
+/*
+Copies the record replacing the fields
+given as keyword arguments
+*/
+func recordWith(rt Runtime, this IrObject, names []string, values []IrObject, args ...IrObject) IrObject {
+	object := this.(*Object)
+
+	if len(args) > 0 {
+		rt.SetError(NewError("with only takes keyword arguments", ArgumentError))
+		return nil
+	}
+
+	copied := make([]IrObject, len(object.values))
+	copy(copied, object.values)
+	for i, name := range names {
+		pos, ok := object.class.fields[name]
+		if !ok {
+			rt.SetError(NewError("'%s' object has no field '%s'", ArgumentError, object.class, name))
+			return nil
+		}
+
+		copied[pos] = values[i]
+	}
+
+	return &Object{
+		base:   &base{class: object.class},
+		values: copied,
+	}
+}
+
+var RecordClass *Class
+
+func InitRecord() {
+	if RecordClass != nil {
+		return
+	}
+
+	RecordClass = NewClass("Record", ObjectClass)
+	RecordClass.AddGoMethod("==", oneArg(recordEqual))
+	RecordClass.AddGoMethod("hash", zeroArgs(recordHash))
+	RecordClass.AddGoMethod("inspect", zeroArgs(recordInspect))
+	RecordClass.AddGoMethod("to_str", zeroArgs(recordInspect))
+	RecordClass.AddGoMethod("with", keywordArgs(recordWith))
+}
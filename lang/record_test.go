@@ -0,0 +1,58 @@
+package lang
+
+import (
+	"testing"
+)
+
+func newTestPoint(x, y IrObject) *Object {
+	class := NewClass("Point", RecordClass)
+	class.AddField(NewString("x"))
+	class.AddField(NewString("y"))
+
+	point := class.Alloc().(*Object)
+	point.Set(0, x)
+	point.Set(1, y)
+	return point
+}
+
+func Test_recordEqual(t *testing.T) {
+	point := newTestPoint(Int(1), NewString("a"))
+
+	same := point.class.Alloc().(*Object)
+	same.Set(0, Int(1))
+	same.Set(1, NewString("a"))
+
+	assertEqual(t, recordEqual(globalTestDummyRuntime, point, same), True)
+	assertEqual(t, recordHash(globalTestDummyRuntime, point), recordHash(globalTestDummyRuntime, same))
+
+	// same fields, but another record
+	assertEqual(t, recordEqual(globalTestDummyRuntime, point, newTestPoint(Int(1), NewString("a"))), False)
+
+	same.Set(0, Int(2))
+	assertEqual(t, recordEqual(globalTestDummyRuntime, point, same), False)
+}
+
+func Test_recordInspect(t *testing.T) {
+	point := newTestPoint(Int(1), NewString("a"))
+	assertEqual(t, recordInspect(globalTestDummyRuntime, point), NewString(`Point(x: 1, y: "a")`))
+}
+
+func Test_recordWith(t *testing.T) {
+	point := newTestPoint(Int(1), Int(2))
+
+	copied := recordWith(globalTestDummyRuntime, point, []string{"y"}, []IrObject{Int(5)}).(*Object)
+
+	assertEqual(t, copied.Get(0), Int(1))
+	assertEqual(t, copied.Get(1), Int(5))
+	assertEqual(t, point.Get(1), Int(2))
+
+	rt := new(dummyRuntime)
+	if result := recordWith(rt, point, []string{"z"}, []IrObject{Int(5)}); result != nil || rt.err == nil {
+		t.Errorf("expected unknown field error, got %v", result)
+	}
+
+	rt = new(dummyRuntime)
+	if result := recordWith(rt, point, nil, nil, Int(5)); result != nil || rt.err == nil {
+		t.Errorf("expected positional argument error, got %v", result)
+	}
+}
@@ -18,13 +18,76 @@ func stringSize(rt Runtime, this IrObject) IrObject {
 	return Int(len(str.Value))
 }
 
+/*
+Reports whether other occurs within the string, sets
+a TypeError when other is not a string
+*/
+func stringInclude(rt Runtime, this IrObject, other IrObject) IrObject {
+	sub, ok := other.(*String)
+	if !ok {
+		rt.SetError(NewTypeError("no implicit conversion of %s into String", other.Class()))
+		return nil
+	}
+
+	return Bool(bytes.Contains(STRING(this).Value, sub.Value))
+}
+
 func stringEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
 	left := STRING(this)
-	right := STRING(rhs)
+	right, ok := rhs.(*String)
+	if !ok {
+		return False
+	}
 
 	return Bool(bytes.Equal(left.Value, right.Value))
 }
 
+/*
+Compares both strings byte by byte, sets a
+TypeError when rhs is not a string
+*/
+func stringCompare(rt Runtime, this IrObject, rhs IrObject) (int, bool) {
+	right, ok := rhs.(*String)
+	if !ok {
+		rt.SetError(NewTypeError("comparison of String with %s failed", rhs.Class()))
+		return 0, false
+	}
+
+	return bytes.Compare(unwrapString(this), right.Value), true
+}
+
+func stringLess(rt Runtime, this IrObject, rhs IrObject) IrObject {
+	if cmp, ok := stringCompare(rt, this, rhs); ok {
+		return Bool(cmp < 0)
+	}
+
+	return nil
+}
+
+func stringLessEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
+	if cmp, ok := stringCompare(rt, this, rhs); ok {
+		return Bool(cmp <= 0)
+	}
+
+	return nil
+}
+
+func stringGreat(rt Runtime, this IrObject, rhs IrObject) IrObject {
+	if cmp, ok := stringCompare(rt, this, rhs); ok {
+		return Bool(cmp > 0)
+	}
+
+	return nil
+}
+
+func stringGreatEqual(rt Runtime, this IrObject, rhs IrObject) IrObject {
+	if cmp, ok := stringCompare(rt, this, rhs); ok {
+		return Bool(cmp >= 0)
+	}
+
+	return nil
+}
+
 func stringPlus(rt Runtime, this IrObject, rhs IrObject) IrObject {
 	var buf strings.Builder
 
@@ -51,10 +114,51 @@ func stringHash(rt Runtime, this IrObject) IrObject {
 	return hash
 }
 
+/*
+Indexes the characters of the string, as its iterator yields
+them, so a multibyte character is never split
+*/
+func stringAt(rt Runtime, this IrObject, index IrObject) IrObject {
+	str := []rune(string(unwrapString(this)))
+
+	switch idx := index.(type) {
+	case Int:
+		pos, err := checkBoundaries(int(idx), len(str))
+		if err != nil {
+			rt.SetError(err)
+			return nil
+		}
+
+		return NewString(string(str[pos]))
+
+	case *Range:
+		start, end, ok := rangeBounds(idx, len(str))
+		if !ok {
+			return None
+		}
+
+		var buf strings.Builder
+		eachIndex(idx, start, end, func(i int) {
+			buf.WriteRune(str[i])
+		})
+
+		return NewString(buf.String())
+
+	default:
+		err := NewTypeError("no implicit conversion of %s into Int", index.Class())
+		rt.SetError(err)
+		return nil
+	}
+}
+
 func stringToString(rt Runtime, this IrObject) IrObject {
 	return this
 }
 
+func stringToSymbol(rt Runtime, this IrObject) IrObject {
+	return Intern(GoString(this))
+}
+
 func stringInspect(rt Runtime, this IrObject) IrObject {
 	bytes := unwrapString(this)
 	var buf strings.Builder
@@ -107,10 +211,18 @@ func InitString() {
 
 	StringClass.AddGoMethod("==", oneArg(stringEqual))
 	StringClass.AddGoMethod("hash", zeroArgs(stringHash))
+	StringClass.AddGoMethod("<", oneArg(stringLess))
+	StringClass.AddGoMethod("<=", oneArg(stringLessEqual))
+	StringClass.AddGoMethod(">", oneArg(stringGreat))
+	StringClass.AddGoMethod(">=", oneArg(stringGreatEqual))
 	StringClass.AddGoMethod("size", zeroArgs(stringSize))
+	StringClass.AddGoMethod("include?", oneArg(stringInclude))
 	StringClass.AddGoMethod("+", oneArg(stringPlus))
+	StringClass.AddGoMethod("at", oneArg(stringAt))
+	StringClass.AddGoMethod("get", oneArg(stringAt))
 	StringClass.AddGoMethod("inspect", zeroArgs(stringInspect))
 	StringClass.AddGoMethod("to_str", zeroArgs(stringToString))
+	StringClass.AddGoMethod("to_sym", zeroArgs(stringToSymbol))
 }
 
 /*
@@ -13,6 +13,7 @@ func Test_stringEqual(t *testing.T) {
 	}{
 		{scenario: "equal", lhs: NewString("a"), rhs: NewString("a"), wantOutput: True},
 		{scenario: "not equal", lhs: NewString("a"), rhs: NewString("aa"), wantOutput: False},
+		{scenario: "not a string", lhs: NewString("1"), rhs: Int(1), wantOutput: False},
 	}
 
 	for _, test := range table {
@@ -44,3 +45,39 @@ func Test_stringPlus(t *testing.T) {
 	assertEqual(t, result, NewString("ab"))
 	assertEqual(t, length, Int(2))
 }
+
+func Test_stringInclude(t *testing.T) {
+	hello := NewString("hello")
+
+	assertEqual(t, stringInclude(globalTestDummyRuntime, hello, NewString("ell")), True)
+	assertEqual(t, stringInclude(globalTestDummyRuntime, hello, NewString("")), True)
+	assertEqual(t, stringInclude(globalTestDummyRuntime, hello, NewString("olé")), False)
+
+	rt := new(dummyRuntime)
+	if result := stringInclude(rt, hello, Int(1)); result != nil {
+		t.Fatalf("expected nil, got %v", result)
+	}
+
+	if rt.err == nil || rt.err.message != "no implicit conversion of Int into String" {
+		t.Errorf("expected TypeError, got %v", rt.err)
+	}
+}
+
+func Test_stringComparison(t *testing.T) {
+	a := NewString("a")
+	b := NewString("b")
+
+	assertEqual(t, stringLess(globalTestDummyRuntime, a, b), True)
+	assertEqual(t, stringLessEqual(globalTestDummyRuntime, a, a), True)
+	assertEqual(t, stringGreat(globalTestDummyRuntime, a, b), False)
+	assertEqual(t, stringGreatEqual(globalTestDummyRuntime, b, a), True)
+
+	rt := new(dummyRuntime)
+	if result := stringLess(rt, a, Int(1)); result != nil {
+		t.Fatalf("expected nil, got %v", result)
+	}
+
+	if rt.err == nil || rt.err.message != "comparison of String with Int failed" {
+		t.Errorf("expected TypeError, got %v", rt.err)
+	}
+}
@@ -0,0 +1,82 @@
+package lang
+
+func SYMBOL(obj IrObject) *Symbol {
+	return obj.(*Symbol)
+}
+
+func symbolHash(rt Runtime, this IrObject) IrObject {
+	return SYMBOL(this).hash
+}
+
+func symbolInspect(rt Runtime, this IrObject) IrObject {
+	return NewString(":" + SYMBOL(this).name)
+}
+
+func symbolToString(rt Runtime, this IrObject) IrObject {
+	return NewString(SYMBOL(this).name)
+}
+
+func symbolSize(rt Runtime, this IrObject) IrObject {
+	return Int(len(SYMBOL(this).name))
+}
+
+var SymbolClass *Class
+
+func InitSymbol() {
+	if SymbolClass != nil {
+		return
+	}
+
+	SymbolClass = NewClass("Symbol", ObjectClass)
+	SymbolClass.AddGoMethod("==", oneArg(objectEqual))
+	SymbolClass.AddGoMethod("hash", zeroArgs(symbolHash))
+	SymbolClass.AddGoMethod("size", zeroArgs(symbolSize))
+	SymbolClass.AddGoMethod("inspect", zeroArgs(symbolInspect))
+	SymbolClass.AddGoMethod("to_str", zeroArgs(symbolToString))
+	SymbolClass.AddGoMethod("to_sym", zeroArgs(returnThis))
+}
+
+/*
+A name written :name, there is a single symbol for each
+name so symbols are compared by identity and their hash
+is only computed once
+*/
+type Symbol struct {
+	*base
+
+	name string
+	hash Int
+}
+
+func (s *Symbol) Name() string {
+	return s.name
+}
+
+func (s *Symbol) String() string {
+	return ":" + s.name
+}
+
+var symbols = make(map[string]*Symbol)
+
+/*
+Returns the symbol for name, creating it the first time
+*/
+func Intern(name string) *Symbol {
+	if symbol, ok := symbols[name]; ok {
+		return symbol
+	}
+
+	var hash Int
+	for i := 0; i < len(name); i++ {
+		hash = 31*hash + Int(name[i])
+	}
+
+	symbol := &Symbol{
+		name: name,
+		hash: hash,
+		base: &base{class: SymbolClass},
+	}
+
+	symbols[name] = symbol
+	return symbol
+}
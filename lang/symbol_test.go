@@ -0,0 +1,24 @@
+package lang
+
+import (
+	"testing"
+)
+
+func Test_Intern(t *testing.T) {
+	symbol := Intern("ready")
+	if Intern("ready") != symbol {
+		t.Error("expected a single symbol for each name")
+	}
+
+	if Intern("done") == symbol {
+		t.Error("expected names to have their own symbol")
+	}
+
+	assertEqual(t, symbolHash(globalTestDummyRuntime, symbol), stringHash(globalTestDummyRuntime, NewString("ready")))
+	assertEqual(t, symbolInspect(globalTestDummyRuntime, symbol), NewString(":ready"))
+	assertEqual(t, symbolToString(globalTestDummyRuntime, symbol), NewString("ready"))
+
+	if stringToSymbol(globalTestDummyRuntime, NewString("ready")) != symbol {
+		t.Error("expected to_sym to return the interned symbol")
+	}
+}
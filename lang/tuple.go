@@ -0,0 +1,111 @@
+package lang
+
+import "strings"
+
+func TUPLE(obj IrObject) *Tuple {
+	return obj.(*Tuple)
+}
+
+func tupleSize(rt Runtime, this IrObject) IrObject {
+	return Int(len(TUPLE(this).Elements))
+}
+
+func tupleAt(rt Runtime, this IrObject, index IrObject) IrObject {
+	tuple := TUPLE(this)
+
+	idx, ok := index.(Int)
+	if !ok {
+		rt.SetError(NewTypeError("no implicit conversion of %s into Int", index.Class()))
+		return nil
+	}
+
+	pos, err := checkBoundaries(int(idx), len(tuple.Elements))
+	if err != nil {
+		rt.SetError(err)
+		return nil
+	}
+
+	return tuple.Elements[pos]
+}
+
+func tupleToArray(rt Runtime, this IrObject) IrObject {
+	tuple := TUPLE(this)
+
+	elements := make([]IrObject, len(tuple.Elements))
+	copy(elements, tuple.Elements)
+	return NewArray(elements)
+}
+
+/*
+Two tuples are equal when they hold equal values in the same
+order, a tuple is never equal to an array
+*/
+func tupleEqual(rt Runtime, this IrObject, other IrObject) IrObject {
+	y, ok := other.(*Tuple)
+	if !ok {
+		return False
+	}
+
+	return arrayEqual(rt, NewArray(TUPLE(this).Elements), NewArray(y.Elements))
+}
+
+func tupleHash(rt Runtime, this IrObject) IrObject {
+	return arrayHash(rt, NewArray(TUPLE(this).Elements))
+}
+
+func tupleInspect(rt Runtime, this IrObject) IrObject {
+	var buf strings.Builder
+
+	buf.WriteByte('(')
+	for i, el := range TUPLE(this).Elements {
+		val := call(rt, el, "inspect")
+		if val == nil {
+			return nil
+		}
+
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.Write(unwrapString(val))
+	}
+	buf.WriteByte(')')
+
+	return NewString(buf.String())
+}
+
+var TupleClass *Class
+
+func InitTuple() {
+	if TupleClass != nil {
+		return
+	}
+
+	TupleClass = NewClass("Tuple", ObjectClass)
+	TupleClass.AddGoMethod("==", oneArg(tupleEqual))
+	TupleClass.AddGoMethod("hash", zeroArgs(tupleHash))
+	TupleClass.AddGoMethod("size", zeroArgs(tupleSize))
+	TupleClass.AddGoMethod("get", oneArg(tupleAt))
+	TupleClass.AddGoMethod("at", oneArg(tupleAt))
+	TupleClass.AddGoMethod("to_a", zeroArgs(tupleToArray))
+	TupleClass.AddGoMethod("inspect", zeroArgs(tupleInspect))
+	TupleClass.AddGoMethod("to_str", zeroArgs(tupleInspect))
+}
+
+/*
+Holds the values of a function returning more than one of them
+when the caller keeps them in a single value. Destructuring a
+tuple unpacks it like an array
+*/
+type Tuple struct {
+	*base
+
+	Elements []IrObject
+}
+
+func NewTuple(elements []IrObject) *Tuple {
+	return &Tuple{
+		Elements: elements,
+		base:     &base{class: TupleClass},
+	}
+}
@@ -0,0 +1,41 @@
+package lang
+
+import (
+	"testing"
+)
+
+func Test_tupleAt(t *testing.T) {
+	tuple := NewTuple([]IrObject{Int(1), Int(2)})
+
+	assertEqual(t, tupleSize(globalTestDummyRuntime, tuple), Int(2))
+	assertEqual(t, tupleAt(globalTestDummyRuntime, tuple, Int(-1)), Int(2))
+
+	rt := new(dummyRuntime)
+	if result := tupleAt(rt, tuple, Int(2)); result != nil || rt.err == nil {
+		t.Errorf("expected out of bounds error, got %v", result)
+	}
+}
+
+func Test_tupleEqual(t *testing.T) {
+	tuple := NewTuple([]IrObject{Int(1), NewString("a")})
+
+	assertEqual(t, tupleEqual(globalTestDummyRuntime, tuple, NewTuple([]IrObject{Int(1), NewString("a")})), True)
+	assertEqual(t, tupleEqual(globalTestDummyRuntime, tuple, NewArray([]IrObject{Int(1), NewString("a")})), False)
+	assertEqual(t, tupleInspect(globalTestDummyRuntime, tuple), NewString(`(1, "a")`))
+}
+
+func Test_UnpackTuple(t *testing.T) {
+	tuple := NewTuple([]IrObject{Int(1), Int(2)})
+
+	elements, err := Unpack(tuple, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.message)
+	}
+
+	assertEqual(t, elements[0], Int(1))
+	assertEqual(t, elements[1], Int(2))
+
+	if _, err := Unpack(tuple, 3); err == nil || err.message != "wrong number of values to unpack (given 2, expected 3)" {
+		t.Errorf("expected unpack error, got %v", err)
+	}
+}
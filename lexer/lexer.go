@@ -1,8 +1,11 @@
 package lexer
 
 import (
+	"fmt"
 	"io"
 	"iracema/token"
+	"strconv"
+	"strings"
 )
 
 type ErrorHandler func(*token.Position, string)
@@ -19,9 +22,16 @@ type lexer struct {
 	position     *token.Position
 	errorHandler ErrorHandler
 	readNewLine  bool
+	last         token.Type
 }
 
 func (l *lexer) NextToken() *token.Token {
+	tok := l.next()
+	l.last = tok.Type
+	return tok
+}
+
+func (l *lexer) next() *token.Token {
 	l.skipWhitespace()
 	position := l.position.Snapshot(l.readOffset)
 	l.readNewLine = false
@@ -42,13 +52,62 @@ func (l *lexer) NextToken() *token.Token {
 		return token.New(token.String, l.readString(), position)
 
 	case '.':
+		if isDigit(l.peek()) {
+			l.advance()
+			_, digits := l.readNumber()
+			literal := "0." + digits
+
+			l.errorHandler(position, "float literal must start with a digit, use "+literal)
+			return token.New(token.Float, literal, position)
+		}
+
 		l.advance()
-		return token.New(token.Dot, "", position)
+		if l.char != '.' {
+			return token.New(token.Dot, "", position)
+		}
+
+		l.advance()
+		if l.char != '.' {
+			return token.New(token.DotDot, "", position)
+		}
+
+		l.advance()
+		return token.New(token.DotDotDot, "", position)
 
 	case ':':
+		// after an operand the colon ends a key, a label or the then of a ?:
+		if isLetter(l.peek()) && !l.followsOperand() {
+			l.advance()
+			return token.New(token.Symbol, l.readIdent(), position)
+		}
+
 		l.advance()
 		return token.New(token.Colon, "", position)
 
+	case '$':
+		l.advance()
+		if !isLetter(l.char) {
+			l.errorHandler(position, "expected a name after $")
+			return token.New(token.Illegal, "", position)
+		}
+
+		return token.New(token.Global, l.readIdent(), position)
+
+	case '?':
+		l.advance()
+		kind := token.Question
+
+		switch l.char {
+		case '.':
+			l.advance()
+			kind = token.QuestionDot
+		case '?':
+			l.advance()
+			kind = token.QuestionQuestion
+		}
+
+		return token.New(kind, "", position)
+
 	case '=':
 		l.advance()
 		kind := token.Assign
@@ -115,24 +174,37 @@ func (l *lexer) NextToken() *token.Token {
 		l.advance()
 		kind := token.Minus
 
-		if l.char == '>' {
+		switch l.char {
+		case '>':
 			l.advance()
 			kind = token.Arrow
+		case '=':
+			l.advance()
+			kind = token.MinusAssign
 		}
 
 		return token.New(kind, "", position)
 
 	case '+':
 		l.advance()
-		return token.New(token.Plus, "", position)
+		return token.New(l.withAssign(token.Plus, token.PlusAssign), "", position)
 
 	case '/':
 		l.advance()
-		return token.New(token.Slash, "", position)
+		return token.New(l.withAssign(token.Slash, token.SlashAssign), "", position)
 
 	case '*':
 		l.advance()
-		return token.New(token.Star, "", position)
+		if l.char == '*' {
+			l.advance()
+			return token.New(token.StarStar, "", position)
+		}
+
+		return token.New(l.withAssign(token.Star, token.StarAssign), "", position)
+
+	case '^':
+		l.advance()
+		return token.New(token.Caret, "", position)
 
 	case '!':
 		l.advance()
@@ -157,6 +229,18 @@ func (l *lexer) NextToken() *token.Token {
 	}
 }
 
+/*
+Returns compound when the operator is followed by =
+*/
+func (l *lexer) withAssign(operator, compound token.Type) token.Type {
+	if l.char == '=' {
+		l.advance()
+		return compound
+	}
+
+	return operator
+}
+
 func (l *lexer) advance() {
 	if l.char == '\n' {
 		l.position.AddLine(l.readOffset)
@@ -172,12 +256,32 @@ func (l *lexer) advance() {
 	}
 }
 
+/*
+Reports whether the last token was a name,
+a literal or a closing bracket
+*/
+func (l *lexer) followsOperand() bool {
+	switch l.last {
+	case
+		token.Ident, token.Global, token.Symbol, token.This, token.None,
+		token.Int, token.Float, token.String, token.Bool,
+		token.RightParen, token.RightBracket, token.RightBrace:
+		return true
+	}
+
+	return false
+}
+
 func (l *lexer) peek() byte {
-	if l.readOffset >= len(l.source) {
+	return l.peekAt(0)
+}
+
+func (l *lexer) peekAt(n int) byte {
+	if l.readOffset+n >= len(l.source) {
 		return 0
 	}
 
-	return l.source[l.readOffset]
+	return l.source[l.readOffset+n]
 }
 
 func (l *lexer) pushBack() {
@@ -224,6 +328,21 @@ func isDigit(char byte) bool {
 	return '0' <= char && char <= '9'
 }
 
+func isHexDigit(char byte) bool {
+	return isDigit(char) || 'a' <= char && char <= 'f' || 'A' <= char && char <= 'F'
+}
+
+func digitValue(char byte) int {
+	switch {
+	case isDigit(char):
+		return int(char - '0')
+	case 'a' <= char && char <= 'f':
+		return int(char - 'a' + 10)
+	default:
+		return int(char - 'A' + 10)
+	}
+}
+
 func (l *lexer) readString() string {
 	l.advance()
 	l.readNewLine = true
@@ -273,33 +392,138 @@ func (l *lexer) readIdent() string {
 		l.advance()
 	}
 
-	if isSpecialChar(l.char) {
+	if isSpecialChar(l.char) && !l.atNoneOperator() {
 		l.advance()
 	}
 
 	return string(l.source[start:l.offset])
 }
 
+/*
+Reports whether the ? after an ident starts ?. or ??. The ?
+of a method name binds first, so a.empty?? b reads as
+a.empty? ? b and empty??.b as empty? ?. b
+*/
+func (l *lexer) atNoneOperator() bool {
+	if l.char != '?' {
+		return false
+	}
+
+	switch l.peek() {
+	case '.':
+		return true
+	case '?':
+		return l.peekAt(1) != '.' && !l.atMethodName()
+	}
+
+	return false
+}
+
+// Reports whether the ident being read names a method
+func (l *lexer) atMethodName() bool {
+	switch l.last {
+	case token.Dot, token.QuestionDot, token.Fun:
+		return true
+	}
+
+	return false
+}
+
 func (l *lexer) readNumber() (token.Type, string) {
 	l.readNewLine = true
 
 	start := l.offset
 	tok := token.Int
 
-	for isDigit(l.char) {
-		l.advance()
+	if l.char == '0' {
+		if base, name := numberBase(l.peek()); base != 10 {
+			l.advance()
+			l.advance()
+
+			// an underscore may follow the base prefix
+			if l.char == '_' && isHexDigit(l.peek()) {
+				l.advance()
+			}
+
+			if !l.readDigits(base, name) {
+				l.errorHandler(l.position.Snapshot(l.offset), name+" literal has no digits")
+			}
+
+			return tok, string(l.source[start:l.offset])
+		}
 	}
 
+	l.readDigits(10, "decimal")
+
 	if l.char == '.' && isDigit(l.peek()) {
 		l.advance()
 		tok = token.Float
+		l.readDigits(10, "decimal")
+	}
 
-		for isDigit(l.char) {
+	if l.char == 'e' || l.char == 'E' {
+		l.advance()
+		tok = token.Float
+
+		if l.char == '+' || l.char == '-' {
 			l.advance()
 		}
+
+		if !l.readDigits(10, "decimal") {
+			l.errorHandler(l.position.Snapshot(l.offset), "exponent has no digits")
+		}
 	}
 
-	return tok, string(l.source[start:l.offset])
+	literal := string(l.source[start:l.offset])
+	if tok == token.Float {
+		_, err := strconv.ParseFloat(strings.ReplaceAll(literal, "_", ""), 64)
+		if err != nil {
+			l.errorHandler(l.position.Snapshot(start+1), "float literal "+literal+" is out of range")
+		}
+	}
+
+	return tok, literal
+}
+
+func numberBase(char byte) (int, string) {
+	switch char {
+	case 'x', 'X':
+		return 16, "hexadecimal"
+	case 'o', 'O':
+		return 8, "octal"
+	case 'b', 'B':
+		return 2, "binary"
+	default:
+		return 10, "decimal"
+	}
+}
+
+func (l *lexer) readDigits(base int, name string) bool {
+	digits := 0
+	for {
+		switch {
+		case l.char == '_':
+			if next := l.peek(); digits == 0 || !isHexDigit(next) || digitValue(next) >= base {
+				l.errorHandler(l.position.Snapshot(l.offset), "'_' must separate successive digits")
+			}
+
+		case isHexDigit(l.char):
+			if digitValue(l.char) >= base {
+				if base == 10 {
+					return digits > 0
+				}
+
+				err := fmt.Sprintf("invalid digit '%c' in %s literal", l.char, name)
+				l.errorHandler(l.position.Snapshot(l.offset), err)
+			}
+			digits++
+
+		default:
+			return digits > 0
+		}
+
+		l.advance()
+	}
 }
 
 func New(input io.Reader, errHandler ErrorHandler) *lexer {
@@ -35,6 +35,16 @@ func TestNextToken(t *testing.T) {
 			ExpectedType:    token.Ident,
 			ExpectedLiteral: "Object",
 		},
+		"symbol": {
+			Input:           bytes.NewBufferString(":ready"),
+			ExpectedType:    token.Symbol,
+			ExpectedLiteral: "ready",
+		},
+		"global": {
+			Input:           bytes.NewBufferString("$retries"),
+			ExpectedType:    token.Global,
+			ExpectedLiteral: "retries",
+		},
 		"empty string": {
 			Input:           bytes.NewBufferString(`""`),
 			ExpectedType:    token.String,
@@ -87,6 +97,14 @@ func TestNextToken(t *testing.T) {
 			Input:        bytes.NewBufferString("."),
 			ExpectedType: token.Dot,
 		},
+		"DotDot": {
+			Input:        bytes.NewBufferString(".."),
+			ExpectedType: token.DotDot,
+		},
+		"DotDotDot": {
+			Input:        bytes.NewBufferString("..."),
+			ExpectedType: token.DotDotDot,
+		},
 		"Colon": {
 			Input:        bytes.NewBufferString(":"),
 			ExpectedType: token.Colon,
@@ -111,6 +129,10 @@ func TestNextToken(t *testing.T) {
 			Input:        bytes.NewBufferString("{"),
 			ExpectedType: token.LeftBrace,
 		},
+		"Caret": {
+			Input:        bytes.NewBufferString("^"),
+			ExpectedType: token.Caret,
+		},
 		"RightBrace": {
 			Input:        bytes.NewBufferString("}"),
 			ExpectedType: token.RightBrace,
@@ -120,6 +142,16 @@ func TestNextToken(t *testing.T) {
 			ExpectedType:    token.Object,
 			ExpectedLiteral: "object",
 		},
+		"keyword enum": {
+			Input:           bytes.NewBufferString("enum"),
+			ExpectedType:    token.Enum,
+			ExpectedLiteral: "enum",
+		},
+		"keyword record": {
+			Input:           bytes.NewBufferString("record"),
+			ExpectedType:    token.Record,
+			ExpectedLiteral: "record",
+		},
 		"keyword fun": {
 			Input:        bytes.NewBufferString("fun"),
 			ExpectedType: token.Fun,
@@ -133,11 +165,46 @@ func TestNextToken(t *testing.T) {
 			ExpectedType:    token.Int,
 			ExpectedLiteral: "10",
 		},
+		"literal int larger than 64 bits": {
+			Input:           bytes.NewBufferString("123456789012345678901234567890"),
+			ExpectedType:    token.Int,
+			ExpectedLiteral: "123456789012345678901234567890",
+		},
 		"literal float": {
 			Input:           bytes.NewBufferString("10.10"),
 			ExpectedType:    token.Float,
 			ExpectedLiteral: "10.10",
 		},
+		"literal hex int": {
+			Input:           bytes.NewBufferString("0xFF"),
+			ExpectedType:    token.Int,
+			ExpectedLiteral: "0xFF",
+		},
+		"literal binary int": {
+			Input:           bytes.NewBufferString("0b1010"),
+			ExpectedType:    token.Int,
+			ExpectedLiteral: "0b1010",
+		},
+		"literal octal int": {
+			Input:           bytes.NewBufferString("0o755"),
+			ExpectedType:    token.Int,
+			ExpectedLiteral: "0o755",
+		},
+		"literal int with underscores": {
+			Input:           bytes.NewBufferString("1_000_000"),
+			ExpectedType:    token.Int,
+			ExpectedLiteral: "1_000_000",
+		},
+		"literal float with negative exponent": {
+			Input:           bytes.NewBufferString("1e-9"),
+			ExpectedType:    token.Float,
+			ExpectedLiteral: "1e-9",
+		},
+		"literal float with fraction and exponent": {
+			Input:           bytes.NewBufferString("2.5E3"),
+			ExpectedType:    token.Float,
+			ExpectedLiteral: "2.5E3",
+		},
 		"Minus": {
 			Input:        bytes.NewBufferString("-"),
 			ExpectedType: token.Minus,
@@ -217,6 +284,26 @@ func TestNextToken(t *testing.T) {
 			Input:        bytes.NewBufferString("*"),
 			ExpectedType: token.Star,
 		},
+		"question": {
+			Input:        bytes.NewBufferString("?"),
+			ExpectedType: token.Question,
+		},
+		"plus assign": {
+			Input:        bytes.NewBufferString("+="),
+			ExpectedType: token.PlusAssign,
+		},
+		"minus assign": {
+			Input:        bytes.NewBufferString("-="),
+			ExpectedType: token.MinusAssign,
+		},
+		"star assign": {
+			Input:        bytes.NewBufferString("*="),
+			ExpectedType: token.StarAssign,
+		},
+		"slash assign": {
+			Input:        bytes.NewBufferString("/="),
+			ExpectedType: token.SlashAssign,
+		},
 		"illegal": {
 			Input:        bytes.NewBufferString("%"),
 			ExpectedType: token.Illegal,
@@ -357,6 +444,83 @@ func TestStringError(t *testing.T) {
 	}
 }
 
+func TestNumberError(t *testing.T) {
+	tests := []struct {
+		Scenario    string
+		Input       string
+		ExpectedErr string
+	}{
+		{
+			Scenario:    "leading dot",
+			Input:       ".5",
+			ExpectedErr: "float literal must start with a digit, use 0.5",
+		},
+		{
+			Scenario:    "hex without digits",
+			Input:       "0x",
+			ExpectedErr: "hexadecimal literal has no digits",
+		},
+		{
+			Scenario:    "invalid binary digit",
+			Input:       "0b102",
+			ExpectedErr: "invalid digit '2' in binary literal",
+		},
+		{
+			Scenario:    "invalid octal digit",
+			Input:       "0o78",
+			ExpectedErr: "invalid digit '8' in octal literal",
+		},
+		{
+			Scenario:    "trailing underscore",
+			Input:       "100_",
+			ExpectedErr: "'_' must separate successive digits",
+		},
+		{
+			Scenario:    "double underscore",
+			Input:       "1__0",
+			ExpectedErr: "'_' must separate successive digits",
+		},
+		{
+			Scenario:    "underscore before exponent",
+			Input:       "1_e5",
+			ExpectedErr: "'_' must separate successive digits",
+		},
+		{
+			Scenario:    "exponent without digits",
+			Input:       "1e",
+			ExpectedErr: "exponent has no digits",
+		},
+		{
+			Scenario:    "signed exponent without digits",
+			Input:       "2.5e+",
+			ExpectedErr: "exponent has no digits",
+		},
+		{
+			Scenario:    "float out of range",
+			Input:       "1.5e400",
+			ExpectedErr: "float literal 1.5e400 is out of range",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Scenario, func(t *testing.T) {
+			var got string
+
+			input := bytes.NewBufferString(test.Input)
+			l := New(input, func(_ *token.Position, err string) {
+				if got == "" {
+					got = err
+				}
+			})
+			l.NextToken()
+
+			if got != test.ExpectedErr {
+				t.Errorf("expected error to be %q, got %q", test.ExpectedErr, got)
+			}
+		})
+	}
+}
+
 func TestSkipComment(t *testing.T) {
 	table := []struct {
 		scenario      string
@@ -456,6 +620,26 @@ func TestLiteralNumberInCallExpr(t *testing.T) {
 			source:   "3.1415.plus",
 			tokens:   []token.Type{token.Float, token.Dot, token.Ident},
 		},
+		{
+			scenario: "literal hex Int",
+			source:   "0xff.plus",
+			tokens:   []token.Type{token.Int, token.Dot, token.Ident},
+		},
+		{
+			scenario: "literal Int followed by ident starting with e",
+			source:   "10.even",
+			tokens:   []token.Type{token.Int, token.Dot, token.Ident},
+		},
+		{
+			scenario: "literal Int in inclusive range",
+			source:   "1..10",
+			tokens:   []token.Type{token.Int, token.DotDot, token.Int},
+		},
+		{
+			scenario: "literal Int in exclusive range",
+			source:   "1...10",
+			tokens:   []token.Type{token.Int, token.DotDotDot, token.Int},
+		},
 		{
 			scenario: "literal Float with single decimal number",
 			source:   "3.1.plus",
@@ -478,3 +662,126 @@ func TestLiteralNumberInCallExpr(t *testing.T) {
 		})
 	}
 }
+
+func TestNoneOperators(t *testing.T) {
+	table := []struct {
+		scenario string
+		source   string
+		tokens   []token.Type
+	}{
+		{
+			scenario: "safe navigation",
+			source:   "user?.name",
+			tokens:   []token.Type{token.Ident, token.QuestionDot, token.Ident},
+		},
+		{
+			scenario: "none coalescing",
+			source:   "name??other",
+			tokens:   []token.Type{token.Ident, token.QuestionQuestion, token.Ident},
+		},
+		{
+			scenario: "predicate method",
+			source:   "list.empty? ? 1 : 2",
+			tokens:   []token.Type{token.Ident, token.Dot, token.Ident, token.Question, token.Int, token.Colon, token.Int},
+		},
+		{
+			scenario: "double star is not a none operator",
+			source:   "{**opts}",
+			tokens:   []token.Type{token.LeftBrace, token.StarStar, token.Ident, token.RightBrace},
+		},
+		{
+			scenario: "safe navigation after predicate method",
+			source:   "list.empty??.to_s",
+			tokens:   []token.Type{token.Ident, token.Dot, token.Ident, token.QuestionDot, token.Ident},
+		},
+		{
+			scenario: "predicate method before a question mark",
+			source:   "list.empty?? 1 : 2",
+			tokens:   []token.Type{token.Ident, token.Dot, token.Ident, token.Question, token.Int, token.Colon, token.Int},
+		},
+		{
+			scenario: "none coalescing after a predicate method",
+			source:   "list.empty??? false",
+			tokens:   []token.Type{token.Ident, token.Dot, token.Ident, token.QuestionQuestion, token.Bool},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.scenario, func(t *testing.T) {
+			input := bytes.NewBufferString(test.source)
+			l := New(input, nil)
+
+			for i, want := range test.tokens {
+				got := l.NextToken()
+
+				if got.Type != want {
+					t.Errorf("expected token at %d to be %s, got %s", i, want, got.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	table := []struct {
+		scenario string
+		source   string
+		tokens   []token.Type
+	}{
+		{
+			scenario: "symbol argument",
+			source:   "send(:name)",
+			tokens:   []token.Type{token.Ident, token.LeftParen, token.Symbol, token.RightParen},
+		},
+		{
+			scenario: "symbol key",
+			source:   "{:name: 1}",
+			tokens:   []token.Type{token.LeftBrace, token.Symbol, token.Colon, token.Int, token.RightBrace},
+		},
+		{
+			scenario: "key glued to its value",
+			source:   "{name:value}",
+			tokens:   []token.Type{token.LeftBrace, token.Ident, token.Colon, token.Ident, token.RightBrace},
+		},
+		{
+			scenario: "case glued to its body",
+			source:   "case 1:puts",
+			tokens:   []token.Type{token.Case, token.Int, token.Colon, token.Ident},
+		},
+		{
+			scenario: "symbols in conditional",
+			source:   "ok ? :a : :b",
+			tokens:   []token.Type{token.Ident, token.Question, token.Symbol, token.Colon, token.Symbol},
+		},
+		{
+			scenario: "else glued to the conditional",
+			source:   "ok ? 1 :other",
+			tokens:   []token.Type{token.Ident, token.Question, token.Int, token.Colon, token.Ident},
+		},
+		{
+			scenario: "else glued after a call",
+			source:   "ok ? f() :g()",
+			tokens:   []token.Type{token.Ident, token.Question, token.Ident, token.LeftParen, token.RightParen, token.Colon, token.Ident},
+		},
+		{
+			scenario: "symbol after a keyword",
+			source:   "return :done",
+			tokens:   []token.Type{token.Return, token.Symbol},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.scenario, func(t *testing.T) {
+			input := bytes.NewBufferString(test.source)
+			l := New(input, nil)
+
+			for i, want := range test.tokens {
+				got := l.NextToken()
+
+				if got.Type != want {
+					t.Errorf("expected token at %d to be %s, got %s", i, want, got.Type)
+				}
+			}
+		})
+	}
+}
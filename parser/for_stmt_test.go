@@ -23,3 +23,55 @@ func TestParseForStmt(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestParseForStmt_withKeyValue(t *testing.T) {
+	code := `for key, value in hash {}`
+
+	stmts := setupTest(t, code, 1)
+
+	forStmt, ok := stmts[0].(*ast.ForStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.ForStmt, got %T", stmts[0])
+	}
+
+	if err := assertIdent(forStmt.Element, "key"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertIdent(forStmt.Value, "value"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertIdent(forStmt.Iterable, "hash"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseForStmt_withLabel(t *testing.T) {
+	code := `outer: for el in elements { for x in el { stop outer } }`
+
+	stmts := setupTest(t, code, 1)
+
+	forStmt, ok := stmts[0].(*ast.ForStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.ForStmt, got %T", stmts[0])
+	}
+
+	if err := assertIdent(forStmt.Label, "outer"); err != nil {
+		t.Error(err)
+	}
+
+	inner := forStmt.Body.Stmts[0].(*ast.ForStmt)
+	if inner.Label != nil {
+		t.Errorf("expected inner loop to have no label, got %s", inner.Label.Value)
+	}
+
+	stop, ok := inner.Body.Stmts[0].(*ast.StopStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.StopStmt, got %T", inner.Body.Stmts[0])
+	}
+
+	if err := assertIdent(stop.Label, "outer"); err != nil {
+		t.Error(err)
+	}
+}
@@ -86,6 +86,86 @@ func TestParse_FunDecl_WithParameter(t *testing.T) {
 	}
 }
 
+func TestParse_FunDecl_WithRestParameter(t *testing.T) {
+	table := []struct {
+		scenario string
+		input    string
+		wantType any
+	}{
+		{scenario: "without type", input: "fun log(level String, *parts) {}"},
+		{scenario: "with type", input: "fun log(level String, *parts Array) {}", wantType: "Array"},
+	}
+
+	for _, row := range table {
+		t.Run(row.scenario, func(t *testing.T) {
+			stmts := setupTest(t, row.input, 1)
+
+			funDecl, ok := stmts[0].(*ast.FunDecl)
+			if !ok {
+				t.Fatalf("expected first stmt to be *ast.FunDecl, got %T", stmts[0])
+			}
+
+			params := funDecl.Type.ParameterList
+			if len(params) != 2 {
+				t.Fatalf("expected 2 params, got %d", len(params))
+			}
+
+			if params[0].Rest {
+				t.Error("expected first param not to be rest")
+			}
+
+			rest := params[1]
+			if !rest.Rest {
+				t.Error("expected last param to be rest")
+			}
+
+			if err := assertIdent(rest.Name, "parts"); err != nil {
+				t.Error(err)
+			}
+
+			if row.wantType == nil {
+				if rest.Type != nil {
+					t.Errorf("expected no type, got %s", rest.Type)
+				}
+			} else if err := assertType(rest.Type, row.wantType); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestParse_FunDecl_WithKeywordParameters(t *testing.T) {
+	stmts := setupTest(t, "fun init(host String, port: 80, tls:) {}", 1)
+
+	funDecl, ok := stmts[0].(*ast.FunDecl)
+	if !ok {
+		t.Fatalf("expected first stmt to be *ast.FunDecl, got %T", stmts[0])
+	}
+
+	params := funDecl.Type.ParameterList
+	if len(params) != 3 {
+		t.Fatalf("expected 3 params, got %d", len(params))
+	}
+
+	if params[0].Keyword {
+		t.Error("expected host not to be a keyword")
+	}
+
+	port := params[1]
+	if err := assertIdent(port.Name, "port"); err != nil {
+		t.Error(err)
+	}
+
+	if !port.Keyword || port.Value == nil || port.Value.String() != "80" {
+		t.Errorf("expected port to be a keyword defaulting to 80, got %s", port)
+	}
+
+	tls := params[2]
+	if !tls.Keyword || tls.Value != nil {
+		t.Errorf("expected tls to be a required keyword, got %s", tls)
+	}
+}
+
 func TestParse_FunDecl_WithParameterizedType(t *testing.T) {
 	table := []struct {
 		scenario string
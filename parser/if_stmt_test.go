@@ -173,3 +173,56 @@ func TestIfWithInvalidElseBlock(t *testing.T) {
 
 	testParserError(t, code, "[Lin: 1 Col: 40] syntax error: expected left brace or if statement")
 }
+
+func TestParseIfExpr(t *testing.T) {
+	stmts := setupTest(t, "x = if ok { 1 } else { 2 }", 1)
+
+	assignStmt, ok := stmts[0].(*ast.AssignStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.AssignStmt, got %T", stmts[0])
+	}
+
+	ifExpr, ok := assignStmt.Right[0].(*ast.IfExpr)
+	if !ok {
+		t.Fatalf("expected to be *ast.IfExpr, got %T", assignStmt.Right[0])
+	}
+
+	if err := assertIdent(ifExpr.Stmt.Cond, "ok"); err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := ifExpr.Stmt.Else.(*ast.BlockStmt); !ok {
+		t.Errorf("expected else to be *ast.BlockStmt, got %T", ifExpr.Stmt.Else)
+	}
+}
+
+func TestParseCondExpr(t *testing.T) {
+	stmts := setupTest(t, "x = a ? 1 : b ? 2 : 3", 1)
+
+	assignStmt, ok := stmts[0].(*ast.AssignStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.AssignStmt, got %T", stmts[0])
+	}
+
+	cond, ok := assignStmt.Right[0].(*ast.CondExpr)
+	if !ok {
+		t.Fatalf("expected to be *ast.CondExpr, got %T", assignStmt.Right[0])
+	}
+
+	if err := assertIdent(cond.Cond, "a"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertLiteral(cond.Then, "1"); err != nil {
+		t.Error(err)
+	}
+
+	nested, ok := cond.Else.(*ast.CondExpr)
+	if !ok {
+		t.Fatalf("expected else to be *ast.CondExpr, got %T", cond.Else)
+	}
+
+	if err := assertIdent(nested.Cond, "b"); err != nil {
+		t.Error(err)
+	}
+}
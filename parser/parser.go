@@ -11,6 +11,8 @@ import (
 
 var startStmt = map[token.Type]bool{
 	token.Object: true,
+	token.Enum:   true,
+	token.Record: true,
 	token.Fun:    true,
 	token.If:     true,
 	token.For:    true,
@@ -26,6 +28,13 @@ var closingToken = map[token.Type]bool{
 	token.RightBracket: true,
 }
 
+// tokens that may follow the ... of a rest target
+var restEnd = map[token.Type]bool{
+	token.Assign:       true,
+	token.Comma:        true,
+	token.RightBracket: true,
+}
+
 var switchStartStmt = map[token.Type]bool{
 	token.Case:       true,
 	token.Colon:      true,
@@ -91,7 +100,7 @@ func (p *parser) parseStmtList() (list []ast.Stmt) {
 		}
 
 		list = append(list, stmt)
-		if !p.consume(token.NewLine) && p.tok.Type != token.RightBrace {
+		if !p.consume(token.NewLine) && !isDone(p.tok) {
 			err := fmt.Sprintf("unexpected %s, expecting } or new line", p.tok)
 			p.setError(p.tok.Position, err)
 			p.sync(startStmt)
@@ -116,6 +125,12 @@ func (p *parser) parseStmt() ast.Stmt {
 	case token.Object:
 		return p.parseObjectDecl()
 
+	case token.Enum:
+		return p.parseEnumDecl()
+
+	case token.Record:
+		return p.parseRecordDecl()
+
 	case token.Var:
 		return p.parseVarDecl()
 
@@ -147,7 +162,7 @@ func (p *parser) parseStmt() ast.Stmt {
 		return p.parseReturnStmt()
 
 	case
-		token.Ident, token.String, token.Bool, token.Int, token.Float,
+		token.Ident, token.Global, token.Symbol, token.String, token.Bool, token.Int, token.Float,
 		token.LeftParen, token.Not, token.Plus, token.Minus,
 		token.LeftBracket, token.LeftBrace, token.None,
 		token.Super, token.This:
@@ -241,6 +256,71 @@ func (p *parser) parseVarDecl() *ast.VarDecl {
 	return decl
 }
 
+/*
+The fields are declared as the parameters of
+a function, the body only holds funs
+*/
+func (p *parser) parseRecordDecl() ast.Stmt {
+	record := &ast.RecordDecl{Token: p.expect(token.Record)}
+	record.Name = p.parseConst()
+	record.FieldList = p.parseParameterList(true)
+
+	if !p.consume(token.LeftBrace) {
+		return record
+	}
+
+	for p.tok.Type != token.RightBrace {
+		switch p.tok.Type {
+		case token.Fun:
+			record.FunctionList = append(record.FunctionList, p.parseFunDecl())
+		case token.NewLine:
+			p.advance()
+
+		default:
+			mesg := fmt.Sprintf("unexpected %s, expecting FunDecl", p.tok)
+			p.setError(p.tok.Position, mesg)
+			return record
+		}
+	}
+
+	p.expect(token.RightBrace)
+
+	return record
+}
+
+/*
+Members are separated by commas or new lines
+*/
+func (p *parser) parseEnumDecl() ast.Stmt {
+	enum := &ast.EnumDecl{Token: p.expect(token.Enum)}
+	enum.Name = p.parseConst()
+
+	p.expect(token.LeftBrace)
+	for p.tok.Type != token.RightBrace {
+		switch p.tok.Type {
+		case token.NewLine, token.Comma:
+			p.advance()
+
+		case token.Ident:
+			member := &ast.EnumMember{Name: p.parseConst()}
+			if p.consume(token.Assign) {
+				member.Value = p.parseExpr()
+			}
+
+			enum.Members = append(enum.Members, member)
+
+		default:
+			mesg := fmt.Sprintf("unexpected %s, expecting an enum member", p.tok)
+			p.setError(p.tok.Position, mesg)
+			return enum
+		}
+	}
+
+	p.expect(token.RightBrace)
+
+	return enum
+}
+
 func (p *parser) parseConstDecl() *ast.ConstDecl {
 	p.expect(token.Const)
 
@@ -299,7 +379,7 @@ func (p *parser) parseFunctionType(wantName, wantParamNames bool) *ast.FunctionT
 
 	sig := new(ast.FunctionType)
 	if wantName {
-		sig.Name = p.parseIdent()
+		sig.Name = p.parseMethodName()
 	}
 
 	sig.ParameterList = p.parseParameterList(wantParamNames)
@@ -376,18 +456,21 @@ func (p *parser) parseWhileStmt() ast.Stmt {
 
 func (p *parser) parseForStmt() ast.Stmt {
 	p.expect(token.For)
-	element := p.parseIdent()
+	stmt := new(ast.ForStmt)
+	stmt.Element = p.parseIdent()
+	if p.consume(token.Comma) {
+		stmt.Value = p.parseIdent()
+	}
+
 	p.expect(token.In)
-	iterator := p.parseExpr()
-	body := p.parseBlockStmt()
+	stmt.Iterable = p.parseExpr()
+	stmt.Body = p.parseBlockStmt()
 
-	return &ast.ForStmt{Element: element, Iterable: iterator, Body: body}
+	return stmt
 }
 
 func (p *parser) parseSwitchStmt() ast.Stmt {
-	p.expect(token.Switch)
-
-	s := new(ast.SwitchStmt)
+	s := &ast.SwitchStmt{Token: p.expect(token.Switch)}
 	s.Key = p.parseExpr()
 	p.expect(token.LeftBrace)
 
@@ -407,14 +490,20 @@ func (p *parser) parseSwitchStmt() ast.Stmt {
 func (p *parser) parseCase() (*ast.CaseClause, bool) {
 	c := new(ast.CaseClause)
 
-	if p.consume(token.Case) {
-		c.Value = p.parseExpr()
+	if p.at(token.Case) {
+		c.Token = p.expect(token.Case)
+		c.Patterns = p.parseExprList()
+		if p.consume(token.If) {
+			c.Guard = p.parseExpr()
+		}
+
 		p.expect(token.Colon)
 		c.Body = &ast.BlockStmt{Stmts: p.parseStmtList()}
 		return c, false
 	}
 
-	if p.consume(token.Default) {
+	if p.at(token.Default) {
+		c.Token = p.expect(token.Default)
 		p.expect(token.Colon)
 		c.Body = &ast.BlockStmt{Stmts: p.parseStmtList()}
 		return c, true
@@ -426,22 +515,51 @@ func (p *parser) parseCase() (*ast.CaseClause, bool) {
 }
 
 func (p *parser) parseStopStmt() ast.Stmt {
-	return &ast.StopStmt{Token: p.expect(token.Stop)}
+	return &ast.StopStmt{Token: p.expect(token.Stop), Label: p.parseLabel()}
 }
 
 func (p *parser) parseNextStmt() ast.Stmt {
-	return &ast.NextStmt{Token: p.expect(token.Next)}
+	return &ast.NextStmt{Token: p.expect(token.Next), Label: p.parseLabel()}
+}
+
+func (p *parser) parseLabel() *ast.Ident {
+	if p.at(token.Ident) {
+		return p.parseIdent()
+	}
+
+	return nil
+}
+
+/*
+Parses the loop following a label
+*/
+func (p *parser) parseLabeledStmt(label *ast.Ident) ast.Stmt {
+	p.expect(token.Colon)
+
+	switch p.tok.Type {
+	case token.While:
+		stmt := p.parseWhileStmt().(*ast.WhileStmt)
+		stmt.Label = label
+		return stmt
+	case token.For:
+		stmt := p.parseForStmt().(*ast.ForStmt)
+		stmt.Label = label
+		return stmt
+	}
+
+	p.setError(p.tok.Position, fmt.Sprintf("expected for or while after label '%s', found '%s'", label.Value, p.tok.Type))
+	return nil
 }
 
 func (p *parser) parseReturnStmt() ast.Stmt {
 	retToken := p.expect(token.Return)
 
-	var value ast.Expr
+	var results []ast.Expr
 	if p.tok.Type != token.NewLine && p.tok.Type != token.RightBrace {
-		value = p.parseExpr()
+		results = p.parseExprList()
 	}
 
-	return &ast.ReturnStmt{Token: retToken, Value: value}
+	return &ast.ReturnStmt{Token: retToken, Results: results}
 }
 
 func (p *parser) parseParameterList(wantNames bool) (list []*ast.VarDecl) {
@@ -462,12 +580,27 @@ func (p *parser) parseParameterList(wantNames bool) (list []*ast.VarDecl) {
 
 func (p *parser) parseParameter(wantName bool) *ast.VarDecl {
 	decl := new(ast.VarDecl)
+	decl.Rest = p.consume(token.Star)
 
 	if wantName {
 		decl.Name = p.parseIdent()
+
+		// keyword parameters without a default value are required
+		if !decl.Rest && p.consume(token.Colon) {
+			decl.Keyword = true
+			if !p.at(token.Comma) && !p.at(token.RightParen) {
+				decl.Value = p.parseExpr()
+			}
+
+			return decl
+		}
+	}
+
+	// rest parameters are always an Array, their type can be left out
+	if !decl.Rest || !p.at(token.Comma) && !p.at(token.RightParen) {
+		decl.Type = p.parseType()
 	}
 
-	decl.Type = p.parseType()
 	if p.consume(token.Assign) {
 		decl.Value = p.parseExpr()
 	}
@@ -479,12 +612,22 @@ func (p *parser) parseSimpleStmt() ast.Stmt {
 	leftExpr := p.parseExprList()
 
 	switch p.tok.Type {
+	case token.Colon:
+		if label, ok := leftExpr[0].(*ast.Ident); ok && len(leftExpr) == 1 {
+			return p.parseLabeledStmt(label)
+		}
 	case token.Assign:
 		return &ast.AssignStmt{
 			Left:  leftExpr,
 			Token: p.expect(token.Assign),
 			Right: p.parseExprList(),
 		}
+	case token.PlusAssign, token.MinusAssign, token.StarAssign, token.SlashAssign:
+		return &ast.AssignStmt{
+			Left:  leftExpr,
+			Token: p.expect(p.tok.Type),
+			Right: p.parseExprList(),
+		}
 	}
 
 	return &ast.ExprStmt{Expr: leftExpr[0]}
@@ -502,7 +645,17 @@ func (p *parser) parseExprList() (list []ast.Expr) {
 }
 
 func (p *parser) parseExpr() ast.Expr {
-	return p.parseBinaryExpr(token.LowestPrecedence)
+	expr := p.parseBinaryExpr(token.LowestPrecedence)
+	if !p.at(token.Question) {
+		return expr
+	}
+
+	cond := &ast.CondExpr{Cond: expr, Question: p.expect(token.Question)}
+	cond.Then = p.parseExpr()
+	p.expect(token.Colon)
+	cond.Else = p.parseExpr()
+
+	return cond
 }
 
 func (p *parser) parseBinaryExpr(precedence int) ast.Expr {
@@ -510,6 +663,11 @@ func (p *parser) parseBinaryExpr(precedence int) ast.Expr {
 
 	for p.tok.Precedence() > precedence {
 		tok := p.expect(p.tok.Type)
+		if tok.Type == token.DotDotDot && restEnd[p.tok.Type] {
+			left = &ast.RestExpr{Expr: left, Ellipsis: tok}
+			continue
+		}
+
 		right := p.parseBinaryExpr(tok.Precedence())
 
 		left = &ast.BinaryExpr{Left: left, Operator: tok, Right: right}
@@ -537,11 +695,12 @@ func (p *parser) parsePrimaryExpr() (expr ast.Expr) {
 
 	for {
 		switch p.tok.Type {
-		case token.Dot:
+		case token.Dot, token.QuestionDot:
 			member := new(ast.MemberExpr)
 			member.Base = expr
-			p.expect(token.Dot)
-			member.Name = p.parseIdent()
+			member.Safe = p.at(token.QuestionDot)
+			p.expect(p.tok.Type)
+			member.Name = p.parseMethodName()
 			expr = member
 
 		case token.LeftParen:
@@ -559,7 +718,7 @@ func (p *parser) parseOperand() ast.Expr {
 	switch p.tok.Type {
 	case
 		token.Int, token.Float, token.String, token.Bool,
-		token.None:
+		token.Symbol, token.None:
 		return p.parseBasicLit()
 
 	case token.Fun:
@@ -568,6 +727,12 @@ func (p *parser) parseOperand() ast.Expr {
 	case token.Ident:
 		return p.parseIdent()
 
+	case token.Global:
+		global := &ast.Global{Token: p.tok, Value: p.tok.Literal}
+		p.advance()
+
+		return global
+
 	case token.LeftParen:
 		return p.parseGroupExpr()
 
@@ -587,6 +752,15 @@ func (p *parser) parseOperand() ast.Expr {
 	case token.Super:
 		return p.parseSuperExpr()
 
+	case token.Caret:
+		return &ast.PinExpr{Caret: p.expect(token.Caret), Name: p.parseIdent()}
+
+	case token.If:
+		return &ast.IfExpr{Stmt: p.parseIfStmt().(*ast.IfStmt)}
+
+	case token.Switch:
+		return &ast.SwitchExpr{Stmt: p.parseSwitchStmt().(*ast.SwitchStmt)}
+
 	default:
 		mesg := fmt.Sprintf("unexpected %s, expecting expression", p.tok)
 		p.setError(p.tok.Position, mesg)
@@ -619,10 +793,23 @@ func (p *parser) parseIdent() *ast.Ident {
 	return &ast.Ident{Token: tok, Value: tok.Literal}
 }
 
+/*
+Method names may also be keywords, so that objects
+can define methods such as next
+*/
+func (p *parser) parseMethodName() *ast.Ident {
+	if tok := p.tok; tok.Type != token.Ident && token.IsKeyword(tok.Literal) {
+		p.advance()
+		return &ast.Ident{Token: tok, Value: tok.Literal}
+	}
+
+	return p.parseIdent()
+}
+
 func (p *parser) parseConst() *ast.Ident {
 	tok := p.expect(token.Ident)
 
-	ident := &ast.Ident{Value: tok.Literal}
+	ident := &ast.Ident{Token: tok, Value: tok.Literal}
 	if !ident.IsConstant() {
 		p.setError(tok.Position, "expected ident to be a constant")
 	}
@@ -642,7 +829,7 @@ func (p *parser) parseArgumentList() (list []ast.Expr) {
 	p.expect(token.LeftParen)
 
 	for p.tok.Type != token.RightParen && p.tok.Type != token.EOF {
-		list = append(list, p.parseExpr())
+		list = append(list, p.parseArgument())
 
 		if !p.consumeCommaOrExpect(token.RightParen) {
 			return
@@ -665,34 +852,78 @@ func (p *parser) consumeCommaOrExpect(next token.Type) bool {
 	return false
 }
 
-func (p *parser) parseArrayLit() (ary *ast.ArrayLit) {
-	ary = new(ast.ArrayLit)
+func (p *parser) parseArrayLit() ast.Expr {
+	ary := new(ast.ArrayLit)
 
 	ary.LeftBracket = p.expect(token.LeftBracket)
 	for p.tok.Type != token.RightBracket && p.tok.Type != token.EOF {
-		ary.Elements = append(ary.Elements, p.parseExpr())
+		ary.Elements = append(ary.Elements, p.parseElement())
+
+		if len(ary.Elements) == 1 && p.at(token.For) {
+			return &ast.ArrayComp{
+				LeftBracket:  ary.LeftBracket,
+				Element:      ary.Elements[0],
+				Clause:       p.parseCompClause(),
+				RightBracket: p.expect(token.RightBracket),
+			}
+		}
 
 		if !p.consumeCommaOrExpect(token.RightBracket) {
-			return
+			return ary
 		}
 	}
 
 	ary.RightBracket = p.expect(token.RightBracket)
-	return
+	return ary
 }
 
-func (p *parser) parseHashLit() *ast.MapLit {
+func (p *parser) parseHashLit() ast.Expr {
+	leftBrace := p.expect(token.LeftBrace)
+	entries := p.parseHashEntries()
+
+	if len(entries) == 1 && p.at(token.For) {
+		return &ast.HashComp{
+			LeftBrace:  leftBrace,
+			Entry:      entries[0],
+			Clause:     p.parseCompClause(),
+			RightBrace: p.expect(token.RightBrace),
+		}
+	}
+
 	return &ast.MapLit{
-		LeftBrace:  p.expect(token.LeftBrace),
-		Entries:    p.parseHashEntries(),
+		LeftBrace:  leftBrace,
+		Entries:    entries,
 		RightBrace: p.expect(token.RightBrace),
 	}
 }
 
+/*
+Parses the for clause of a comprehension
+*/
+func (p *parser) parseCompClause() *ast.CompClause {
+	clause := &ast.CompClause{Token: p.expect(token.For)}
+	clause.Element = p.parseIdent()
+	if p.consume(token.Comma) {
+		clause.Value = p.parseIdent()
+	}
+
+	p.expect(token.In)
+	clause.Iterable = p.parseExpr()
+	if p.consume(token.If) {
+		clause.Cond = p.parseExpr()
+	}
+
+	return clause
+}
+
 func (p *parser) parseHashEntries() (list []*ast.HashEntry) {
 	for p.tok.Type != token.RightBrace {
 		list = append(list, p.parseHashEntry())
 
+		if len(list) == 1 && p.at(token.For) {
+			return
+		}
+
 		if !p.consumeCommaOrExpect(token.RightBrace) {
 			return
 		}
@@ -701,7 +932,37 @@ func (p *parser) parseHashEntries() (list []*ast.HashEntry) {
 	return
 }
 
+/*
+Parses an argument or an array element,
+either of which can spread an array
+*/
+func (p *parser) parseElement() ast.Expr {
+	if p.at(token.Star) {
+		return &ast.SplatExpr{Star: p.expect(token.Star), Expr: p.parseExpr()}
+	}
+
+	return p.parseExpr()
+}
+
+/*
+Parses an argument, which can also be passed by name
+*/
+func (p *parser) parseArgument() ast.Expr {
+	arg := p.parseElement()
+	if ident, ok := arg.(*ast.Ident); ok && p.at(token.Colon) {
+		return &ast.KeywordArg{Name: ident, Colon: p.expect(token.Colon), Value: p.parseExpr()}
+	}
+
+	return arg
+}
+
 func (p *parser) parseHashEntry() *ast.HashEntry {
+	if p.at(token.StarStar) {
+		return &ast.HashEntry{
+			Key: &ast.SplatExpr{Star: p.expect(token.StarStar), Expr: p.parseExpr()},
+		}
+	}
+
 	return &ast.HashEntry{
 		Key:   p.parseExpr(),
 		Colon: p.expect(token.Colon),
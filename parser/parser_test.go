@@ -82,6 +82,78 @@ func TestParseAssignStmt(t *testing.T) {
 
 }
 
+func TestParseAssignStmt_withDestructuring(t *testing.T) {
+	stmts := setupTest(t, "[a, b], rest... = list", 1)
+
+	assignStmt, ok := stmts[0].(*ast.AssignStmt)
+	if !ok {
+		t.Fatalf("expected to be a *ast.AssignStmt, got %T", stmts[0])
+	}
+
+	if len(assignStmt.Left) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(assignStmt.Left))
+	}
+
+	nested, ok := assignStmt.Left[0].(*ast.ArrayLit)
+	if !ok {
+		t.Fatalf("expected first target to be *ast.ArrayLit, got %T", assignStmt.Left[0])
+	}
+
+	for i, name := range []string{"a", "b"} {
+		if err := assertIdent(nested.Elements[i], name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	rest, ok := assignStmt.Left[1].(*ast.RestExpr)
+	if !ok {
+		t.Fatalf("expected second target to be *ast.RestExpr, got %T", assignStmt.Left[1])
+	}
+
+	if err := assertIdent(rest.Expr, "rest"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertIdent(assignStmt.Right[0], "list"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseAssignStmt_withCompoundOperator(t *testing.T) {
+	tests := []struct {
+		Code     string
+		Expected token.Type
+	}{
+		{Code: "a += 1", Expected: token.PlusAssign},
+		{Code: "a -= 1", Expected: token.MinusAssign},
+		{Code: "a *= 1", Expected: token.StarAssign},
+		{Code: "a /= 1", Expected: token.SlashAssign},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Code, func(t *testing.T) {
+			stmts := setupTest(t, test.Code, 1)
+
+			assignStmt, ok := stmts[0].(*ast.AssignStmt)
+			if !ok {
+				t.Fatalf("expected to be a *ast.AssignStmt, got %T", stmts[0])
+			}
+
+			if assignStmt.Token.Type != test.Expected {
+				t.Errorf("expected token to be %s, got %s", test.Expected, assignStmt.Token.Type)
+			}
+
+			if err := assertIdent(assignStmt.Left[0], "a"); err != nil {
+				t.Error(err)
+			}
+
+			if err := assertLiteral(assignStmt.Right[0], "1"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
 func TestBinaryExpr(t *testing.T) {
 	tests := []struct {
 		Scenario           string
@@ -167,6 +239,20 @@ func TestBinaryExpr(t *testing.T) {
 			ExpectedOperator:   token.LessEqual,
 			ExpectedRightValue: "2",
 		},
+		{
+			Scenario:           "inclusive range",
+			Code:               "1..10",
+			ExpectedLeftValue:  "1",
+			ExpectedOperator:   token.DotDot,
+			ExpectedRightValue: "10",
+		},
+		{
+			Scenario:           "exclusive range",
+			Code:               "1...10",
+			ExpectedLeftValue:  "1",
+			ExpectedOperator:   token.DotDotDot,
+			ExpectedRightValue: "10",
+		},
 	}
 
 	for _, test := range tests {
@@ -228,6 +314,11 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{Code: "!!true", ExpectedOutput: "(!(!true))"},
 		{Code: "-10 * 10", ExpectedOutput: "((-10)*10)"},
 		{Code: "10 + -10 * 10", ExpectedOutput: "(10+((-10)*10))"},
+		{Code: "1..n + 1", ExpectedOutput: "(1..(n+1))"},
+		{Code: "0...2 * n", ExpectedOutput: "(0...(2*n))"},
+		{Code: "x + 1 is Int", ExpectedOutput: "((x+1)isInt)"},
+		{Code: "x in xs and y", ExpectedOutput: "((xinxs)andy)"},
+		{Code: "1 in 0..n", ExpectedOutput: "(1in(0..n))"},
 	}
 
 	for _, test := range tests {
@@ -277,6 +368,12 @@ func TestParse_CallExpr_withMemberExpr(t *testing.T) {
 			wantName:      "println",
 			wantArguments: []string{"Hello World"},
 		},
+		{
+			input:         "iter.next()",
+			wantBase:      "iter",
+			wantName:      "next",
+			wantArguments: []string{},
+		},
 	}
 
 	for i, row := range table {
@@ -616,11 +713,34 @@ func TestParse_ReturnStmt(t *testing.T) {
 		t.Errorf("expected first stmt to be *ast.ReturnStmt, got %T", stmts[0])
 	}
 
-	if err := assertLiteral(returnStmt.Value, "10"); err != nil {
+	if len(returnStmt.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(returnStmt.Results))
+	}
+
+	if err := assertLiteral(returnStmt.Results[0], "10"); err != nil {
 		t.Error(err)
 	}
 }
 
+func TestParse_ReturnStmt_withManyValues(t *testing.T) {
+	stmts := setupTest(t, "fun pair() { return 1, x - y }", 1)
+
+	funDecl := assertFunDecl(t, stmts[0], "pair", nil)
+
+	returnStmt, ok := funDecl.Body.Stmts[0].(*ast.ReturnStmt)
+	if !ok {
+		t.Fatalf("expected first stmt to be *ast.ReturnStmt, got %T", funDecl.Body.Stmts[0])
+	}
+
+	if len(returnStmt.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(returnStmt.Results))
+	}
+
+	if returnStmt.String() != "return 1, (x-y)" {
+		t.Errorf("unexpected return stmt %s", returnStmt.String())
+	}
+}
+
 func TestParse_ReturnStmt_withoutValue(t *testing.T) {
 	stmts := setupTest(t, "fun do_stuff() { return }", 1)
 
@@ -631,8 +751,8 @@ func TestParse_ReturnStmt_withoutValue(t *testing.T) {
 		t.Errorf("expected first stmt to be *ast.ReturnStmt, got %T", stmts[0])
 	}
 
-	if returnStmt.Value != nil {
-		t.Errorf("expected .Value to be nil, got %T", returnStmt.Value)
+	if returnStmt.Results != nil {
+		t.Errorf("expected .Results to be nil, got %v", returnStmt.Results)
 	}
 }
 
@@ -653,3 +773,340 @@ func TestParse_MemberExpr(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParse_SafeMemberExpr(t *testing.T) {
+	stmts := setupTest(t, "user?.name() ?? \"anonymous\"", 1)
+
+	exprStmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("expected *ast.ExprStmt, got %T", stmts[0])
+	}
+
+	binary, ok := exprStmt.Expr.(*ast.BinaryExpr)
+	if !ok {
+		t.Fatalf("expected *ast.BinaryExpr, got %T", exprStmt.Expr)
+	}
+
+	if binary.Operator.Type != token.QuestionQuestion {
+		t.Errorf("expected operator to be %s, got %s", token.QuestionQuestion, binary.Operator.Type)
+	}
+
+	call, ok := binary.Left.(*ast.CallExpr)
+	if !ok {
+		t.Fatalf("expected *ast.CallExpr, got %T", binary.Left)
+	}
+
+	member, ok := call.Function.(*ast.MemberExpr)
+	if !ok {
+		t.Fatalf("expected *ast.MemberExpr, got %T", call.Function)
+	}
+
+	if !member.Safe {
+		t.Error("expected member expr to be safe")
+	}
+
+	if err := assertIdent(member.Base, "user"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParse_ArrayComp(t *testing.T) {
+	stmts := setupTest(t, "[x * 2 for x in xs if x > 0]", 1)
+
+	exprStmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("expected *ast.ExprStmt, got %T", stmts[0])
+	}
+
+	comp, ok := exprStmt.Expr.(*ast.ArrayComp)
+	if !ok {
+		t.Fatalf("expected *ast.ArrayComp, got %T", exprStmt.Expr)
+	}
+
+	if comp.Element.String() != "(x*2)" {
+		t.Errorf("expected element to be (x*2), got %s", comp.Element)
+	}
+
+	if err := assertIdent(comp.Clause.Element, "x"); err != nil {
+		t.Error(err)
+	}
+
+	if comp.Clause.Value != nil {
+		t.Errorf("expected no value, got %s", comp.Clause.Value)
+	}
+
+	if err := assertIdent(comp.Clause.Iterable, "xs"); err != nil {
+		t.Error(err)
+	}
+
+	if comp.Clause.Cond == nil || comp.Clause.Cond.String() != "(x>0)" {
+		t.Errorf("expected cond to be (x>0), got %v", comp.Clause.Cond)
+	}
+}
+
+func TestParse_HashComp(t *testing.T) {
+	stmts := setupTest(t, "{k: v for k, v in pairs}", 1)
+
+	exprStmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("expected *ast.ExprStmt, got %T", stmts[0])
+	}
+
+	comp, ok := exprStmt.Expr.(*ast.HashComp)
+	if !ok {
+		t.Fatalf("expected *ast.HashComp, got %T", exprStmt.Expr)
+	}
+
+	if err := assertIdent(comp.Entry.Key, "k"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertIdent(comp.Entry.Value, "v"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertIdent(comp.Clause.Element, "k"); err != nil {
+		t.Error(err)
+	}
+
+	if err := assertIdent(comp.Clause.Value, "v"); err != nil {
+		t.Error(err)
+	}
+
+	if comp.Clause.Cond != nil {
+		t.Errorf("expected no cond, got %s", comp.Clause.Cond)
+	}
+}
+
+func TestParse_Globals(t *testing.T) {
+	stmts := setupTest(t, "$retries += 1\nconst LIMIT = $retries", 2)
+
+	assign, ok := stmts[0].(*ast.AssignStmt)
+	if !ok {
+		t.Fatalf("expected *ast.AssignStmt, got %T", stmts[0])
+	}
+
+	global, ok := assign.Left[0].(*ast.Global)
+	if !ok {
+		t.Fatalf("expected *ast.Global, got %T", assign.Left[0])
+	}
+
+	if global.Value != "retries" || global.String() != "$retries" {
+		t.Errorf("expected global $retries, got %s", global)
+	}
+
+	decl, ok := stmts[1].(*ast.ConstDecl)
+	if !ok {
+		t.Fatalf("expected *ast.ConstDecl, got %T", stmts[1])
+	}
+
+	if err := assertIdent(decl.Name, "LIMIT"); err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := decl.Value.(*ast.Global); !ok {
+		t.Errorf("expected const value to be *ast.Global, got %T", decl.Value)
+	}
+}
+
+func TestParse_EnumDecl(t *testing.T) {
+	stmts := setupTest(t, "enum Status {\n  Ok = 200, NotFound = 404\n  Unknown\n}", 1)
+
+	enum, ok := stmts[0].(*ast.EnumDecl)
+	if !ok {
+		t.Fatalf("expected *ast.EnumDecl, got %T", stmts[0])
+	}
+
+	if err := assertIdent(enum.Name, "Status"); err != nil {
+		t.Error(err)
+	}
+
+	if len(enum.Members) != 3 {
+		t.Fatalf("expected 3 members, got %d", len(enum.Members))
+	}
+
+	for i, name := range []string{"Ok", "NotFound", "Unknown"} {
+		if err := assertIdent(enum.Members[i].Name, name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, ok := enum.Members[0].Value.(*ast.BasicLit); !ok {
+		t.Errorf("expected Ok value to be *ast.BasicLit, got %T", enum.Members[0].Value)
+	}
+
+	if enum.Members[2].Value != nil {
+		t.Errorf("expected Unknown to have no value, got %s", enum.Members[2].Value)
+	}
+}
+
+func TestParse_RecordDecl(t *testing.T) {
+	stmts := setupTest(t, "record Point(x Int, y Int) {\n  fun norm() {}\n}\nrecord Empty()", 2)
+
+	record, ok := stmts[0].(*ast.RecordDecl)
+	if !ok {
+		t.Fatalf("expected *ast.RecordDecl, got %T", stmts[0])
+	}
+
+	if err := assertIdent(record.Name, "Point"); err != nil {
+		t.Error(err)
+	}
+
+	if len(record.FieldList) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(record.FieldList))
+	}
+
+	for i, name := range []string{"x", "y"} {
+		if err := assertIdent(record.FieldList[i].Name, name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if len(record.FunctionList) != 1 {
+		t.Errorf("expected 1 fun, got %d", len(record.FunctionList))
+	}
+
+	empty, ok := stmts[1].(*ast.RecordDecl)
+	if !ok || len(empty.FieldList) != 0 || len(empty.FunctionList) != 0 {
+		t.Errorf("expected an empty record, got %v", stmts[1])
+	}
+}
+
+func TestParse_Symbols(t *testing.T) {
+	stmts := setupTest(t, ":ready\nh = {name: :ada}", 2)
+
+	expr, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("expected *ast.ExprStmt, got %T", stmts[0])
+	}
+
+	if err := assertLiteral(expr.Expr, "ready"); err != nil {
+		t.Error(err)
+	}
+
+	assign, ok := stmts[1].(*ast.AssignStmt)
+	if !ok {
+		t.Fatalf("expected *ast.AssignStmt, got %T", stmts[1])
+	}
+
+	entry := assign.Right[0].(*ast.MapLit).Entries[0]
+	if err := assertIdent(entry.Key, "name"); err != nil {
+		t.Error(err)
+	}
+
+	if lit, ok := entry.Value.(*ast.BasicLit); !ok || lit.Type() != token.Symbol {
+		t.Errorf("expected value to be a symbol, got %v", entry.Value)
+	}
+}
+
+func TestParse_SplatExpr(t *testing.T) {
+	table := []struct {
+		scenario string
+		input    string
+		elements func(ast.Expr) []ast.Expr
+	}{
+		{
+			scenario: "call arguments",
+			input:    "log(level, *parts)",
+			elements: func(expr ast.Expr) []ast.Expr { return expr.(*ast.CallExpr).Arguments },
+		},
+		{
+			scenario: "array literal",
+			input:    "[level, *parts]",
+			elements: func(expr ast.Expr) []ast.Expr { return expr.(*ast.ArrayLit).Elements },
+		},
+		{
+			scenario: "hash literal",
+			input:    "{level: 1, **parts}",
+			elements: func(expr ast.Expr) []ast.Expr {
+				var keys []ast.Expr
+				for _, entry := range expr.(*ast.MapLit).Entries {
+					keys = append(keys, entry.Key)
+				}
+				return keys
+			},
+		},
+	}
+
+	for _, row := range table {
+		t.Run(row.scenario, func(t *testing.T) {
+			stmts := setupTest(t, row.input, 1)
+
+			exprStmt, ok := stmts[0].(*ast.ExprStmt)
+			if !ok {
+				t.Fatalf("expected *ast.ExprStmt, got %T", stmts[0])
+			}
+
+			elements := row.elements(exprStmt.Expr)
+			if len(elements) != 2 {
+				t.Fatalf("expected 2 elements, got %d", len(elements))
+			}
+
+			if err := assertIdent(elements[0], "level"); err != nil {
+				t.Error(err)
+			}
+
+			splat, ok := elements[1].(*ast.SplatExpr)
+			if !ok {
+				t.Fatalf("expected *ast.SplatExpr, got %T", elements[1])
+			}
+
+			if err := assertIdent(splat.Expr, "parts"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestParse_KeywordArg(t *testing.T) {
+	stmts := setupTest(t, "Server.new(host, tls: x > 1)", 1)
+
+	exprStmt, ok := stmts[0].(*ast.ExprStmt)
+	if !ok {
+		t.Fatalf("expected *ast.ExprStmt, got %T", stmts[0])
+	}
+
+	call, ok := exprStmt.Expr.(*ast.CallExpr)
+	if !ok {
+		t.Fatalf("expected *ast.CallExpr, got %T", exprStmt.Expr)
+	}
+
+	if len(call.Arguments) != 2 {
+		t.Fatalf("expected 2 arguments, got %d", len(call.Arguments))
+	}
+
+	if err := assertIdent(call.Arguments[0], "host"); err != nil {
+		t.Error(err)
+	}
+
+	kw, ok := call.Arguments[1].(*ast.KeywordArg)
+	if !ok {
+		t.Fatalf("expected *ast.KeywordArg, got %T", call.Arguments[1])
+	}
+
+	if err := assertIdent(kw.Name, "tls"); err != nil {
+		t.Error(err)
+	}
+
+	if kw.Value.String() != "(x>1)" {
+		t.Errorf("expected value to be (x>1), got %s", kw.Value)
+	}
+}
+
+func TestParse_FunDecl_withKeywordName(t *testing.T) {
+	stmts := setupTest(t, "object Pages { fun next() { return 1 } }", 1)
+
+	obj, ok := stmts[0].(*ast.ObjectDecl)
+	if !ok {
+		t.Fatalf("expected first stmt to be *ast.ObjectDecl, got %T", stmts[0])
+	}
+
+	if len(obj.FunctionList) != 1 {
+		t.Fatalf("expected 1 function, got %d", len(obj.FunctionList))
+	}
+
+	fun := obj.FunctionList[0]
+	if err := assertIdent(fun.Type.Name, "next"); err != nil {
+		t.Error(err)
+	}
+}
@@ -175,3 +175,95 @@ func TestSwitch_WithInvalidBlock(t *testing.T) {
 
 	testParserError(t, code, "[Lin: 1 Col: 13] syntax error: expected case, default or }")
 }
+
+func TestParse_SwitchStmt_withPatterns(t *testing.T) {
+	code := `switch value {
+			   case 1, 2, 3: puts(1)
+			   case [first, _]: puts(first)
+			   case {status: s} if s > 400: puts(s)
+			 }`
+
+	stmts := setupTest(t, code, 1)
+
+	switchStmt, ok := stmts[0].(*ast.SwitchStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.SwitchStmt, got %T", stmts[0])
+	}
+
+	if len(switchStmt.Cases) != 3 {
+		t.Fatalf("expected 3 cases, got %d", len(switchStmt.Cases))
+	}
+
+	alternatives := switchStmt.Cases[0].Patterns
+	if len(alternatives) != 3 {
+		t.Fatalf("expected 3 patterns, got %d", len(alternatives))
+	}
+
+	for i, want := range []string{"1", "2", "3"} {
+		if err := assertLiteral(alternatives[i], want); err != nil {
+			t.Error(err)
+		}
+	}
+
+	array, ok := switchStmt.Cases[1].Patterns[0].(*ast.ArrayLit)
+	if !ok {
+		t.Fatalf("expected to be *ast.ArrayLit, got %T", switchStmt.Cases[1].Patterns[0])
+	}
+
+	if err := assertIdent(array.Elements[1], "_"); err != nil {
+		t.Error(err)
+	}
+
+	guarded := switchStmt.Cases[2]
+	if _, ok := guarded.Patterns[0].(*ast.MapLit); !ok {
+		t.Errorf("expected to be *ast.MapLit, got %T", guarded.Patterns[0])
+	}
+
+	if _, ok := guarded.Guard.(*ast.BinaryExpr); !ok {
+		t.Errorf("expected guard to be *ast.BinaryExpr, got %T", guarded.Guard)
+	}
+
+	if switchStmt.Cases[0].Guard != nil {
+		t.Errorf("expected first case to have no guard")
+	}
+}
+
+func TestParse_SwitchStmt_withPinnedLocal(t *testing.T) {
+	stmts := setupTest(t, "switch value { case ^expected: puts(1) }", 1)
+
+	pin, ok := stmts[0].(*ast.SwitchStmt).Cases[0].Patterns[0].(*ast.PinExpr)
+	if !ok {
+		t.Fatalf("expected to be *ast.PinExpr, got %T", stmts[0].(*ast.SwitchStmt).Cases[0].Patterns[0])
+	}
+
+	if err := assertIdent(pin.Name, "expected"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseSwitchExpr(t *testing.T) {
+	stmts := setupTest(t, `label = switch code { case 1: "one" default: "other" }`, 1)
+
+	assignStmt, ok := stmts[0].(*ast.AssignStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.AssignStmt, got %T", stmts[0])
+	}
+
+	switchExpr, ok := assignStmt.Right[0].(*ast.SwitchExpr)
+	if !ok {
+		t.Fatalf("expected to be *ast.SwitchExpr, got %T", assignStmt.Right[0])
+	}
+
+	if len(switchExpr.Stmt.Cases) != 1 {
+		t.Fatalf("expected 1 case, got %d", len(switchExpr.Stmt.Cases))
+	}
+
+	body := switchExpr.Stmt.Cases[0].Body.Stmts
+	if len(body) != 1 {
+		t.Fatalf("expected case body to have 1 stmt, got %d", len(body))
+	}
+
+	if switchExpr.Stmt.Default == nil {
+		t.Fatal("expected switch to have a default")
+	}
+}
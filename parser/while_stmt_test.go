@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"iracema/ast"
 	"iracema/token"
 	"testing"
@@ -64,3 +65,36 @@ func TestParseWhileStmtWithStopStmt(t *testing.T) {
 		t.Errorf("expected operator to be *ast.StopStmt., got %T", whileStmt.Body.Stmts[0])
 	}
 }
+
+func TestParseWhileStmt_withLabel(t *testing.T) {
+	code := "loop: while true { next loop }"
+
+	stmts := setupTest(t, code, 1)
+
+	whileStmt, ok := stmts[0].(*ast.WhileStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.WhileStmt, got %T", stmts[0])
+	}
+
+	if err := assertIdent(whileStmt.Label, "loop"); err != nil {
+		t.Error(err)
+	}
+
+	next, ok := whileStmt.Body.Stmts[0].(*ast.NextStmt)
+	if !ok {
+		t.Fatalf("expected to be *ast.NextStmt, got %T", whileStmt.Body.Stmts[0])
+	}
+
+	if err := assertIdent(next.Label, "loop"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseLabeledStmt_withoutLoop(t *testing.T) {
+	_, err := Parse(bytes.NewBufferString("label: puts(1)"))
+
+	expected := "[Lin: 1 Col: 8] syntax error: expected for or while after label 'label', found 'Ident'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
@@ -27,6 +27,16 @@ var keywords = map[string]Type{
 	"use":     Use,
 	"var":     Var,
 	"const":   Const,
+	"enum":    Enum,
+	"record":  Record,
+}
+
+// binary operators applied by the compound assignments
+var compoundOperators = map[Type]Type{
+	PlusAssign:  Plus,
+	MinusAssign: Minus,
+	StarAssign:  Star,
+	SlashAssign: Slash,
 }
 
 const LowestPrecedence = 0
@@ -39,14 +49,18 @@ type Token struct {
 
 func (t *Token) Precedence() int {
 	switch t.Type {
+	case QuestionQuestion:
+		return 1
 	case And, Or:
 		return 2
-	case Equal, NotEqual, Less, LessEqual, Great, GreatEqual:
+	case Equal, NotEqual, Less, LessEqual, Great, GreatEqual, Is, In:
 		return 3
-	case Minus, Plus:
+	case DotDot, DotDotDot:
 		return 4
-	case Slash, Star:
+	case Minus, Plus:
 		return 5
+	case Slash, Star:
+		return 6
 	}
 
 	return LowestPrecedence
@@ -66,6 +80,19 @@ func Lookup(ident string) Type {
 	return Ident
 }
 
+/*
+Returns the operator applied by a compound assignment
+*/
+func CompoundOperator(kind Type) (Type, bool) {
+	op, ok := compoundOperators[kind]
+	return op, ok
+}
+
+func IsKeyword(ident string) bool {
+	_, ok := keywords[ident]
+	return ok
+}
+
 func New(tokenType Type, literal string, pos *Position) *Token {
 	return &Token{
 		Type:     tokenType,
@@ -47,6 +47,7 @@ func TestToken_Precedence(t *testing.T) {
 		Tok                *Token
 		ExpectedPrecedence int
 	}{
+		{Tok: &Token{Type: QuestionQuestion}, ExpectedPrecedence: 1},
 		{Tok: &Token{Type: Or}, ExpectedPrecedence: 2},
 		{Tok: &Token{Type: And}, ExpectedPrecedence: 2},
 		{Tok: &Token{Type: Equal}, ExpectedPrecedence: 3},
@@ -54,10 +55,14 @@ func TestToken_Precedence(t *testing.T) {
 		{Tok: &Token{Type: LessEqual}, ExpectedPrecedence: 3},
 		{Tok: &Token{Type: Great}, ExpectedPrecedence: 3},
 		{Tok: &Token{Type: GreatEqual}, ExpectedPrecedence: 3},
-		{Tok: &Token{Type: Minus}, ExpectedPrecedence: 4},
-		{Tok: &Token{Type: Plus}, ExpectedPrecedence: 4},
-		{Tok: &Token{Type: Slash}, ExpectedPrecedence: 5},
-		{Tok: &Token{Type: Star}, ExpectedPrecedence: 5},
+		{Tok: &Token{Type: Is}, ExpectedPrecedence: 3},
+		{Tok: &Token{Type: In}, ExpectedPrecedence: 3},
+		{Tok: &Token{Type: DotDot}, ExpectedPrecedence: 4},
+		{Tok: &Token{Type: DotDotDot}, ExpectedPrecedence: 4},
+		{Tok: &Token{Type: Minus}, ExpectedPrecedence: 5},
+		{Tok: &Token{Type: Plus}, ExpectedPrecedence: 5},
+		{Tok: &Token{Type: Slash}, ExpectedPrecedence: 6},
+		{Tok: &Token{Type: Star}, ExpectedPrecedence: 6},
 		{Tok: &Token{Type: Return}, ExpectedPrecedence: 0},
 	}
 
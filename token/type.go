@@ -32,6 +32,8 @@ const (
 	Use     // use
 	Var     // var
 	Const   // const
+	Enum    // enum
+	Record  // record
 
 	Int    // Int
 	Float  // Float
@@ -43,13 +45,25 @@ const (
 	Slash // /
 	Star  // *
 
-	Dot     // .
-	Colon   // :
-	NewLine // \n
-	Not     // !
-	Arrow   // ->
+	StarStar // **
+
+	Dot              // .
+	DotDot           // ..
+	DotDotDot        // ...
+	Colon            // :
+	Question         // ?
+	QuestionDot      // ?.
+	QuestionQuestion // ??
+	NewLine          // \n
+	Not              // !
+	Caret            // ^
+	Arrow            // ->
 	Comma
 	Assign       // =
+	PlusAssign   // +=
+	MinusAssign  // -=
+	StarAssign   // *=
+	SlashAssign  // /=
 	Equal        // ==
 	NotEqual     // !=
 	Less         // <
@@ -57,6 +71,8 @@ const (
 	Great        // >
 	GreatEqual   // >=
 	Ident        // Ident
+	Global       // Global
+	Symbol       // Symbol
 	LeftParen    // (
 	RightParen   // )
 	LeftBracket  // [
@@ -34,39 +34,54 @@ func _() {
 	_ = x[Use-24]
 	_ = x[Var-25]
 	_ = x[Const-26]
-	_ = x[Int-27]
-	_ = x[Float-28]
-	_ = x[String-29]
-	_ = x[Bool-30]
-	_ = x[Minus-31]
-	_ = x[Plus-32]
-	_ = x[Slash-33]
-	_ = x[Star-34]
-	_ = x[Dot-35]
-	_ = x[Colon-36]
-	_ = x[NewLine-37]
-	_ = x[Not-38]
-	_ = x[Arrow-39]
-	_ = x[Comma-40]
-	_ = x[Assign-41]
-	_ = x[Equal-42]
-	_ = x[NotEqual-43]
-	_ = x[Less-44]
-	_ = x[LessEqual-45]
-	_ = x[Great-46]
-	_ = x[GreatEqual-47]
-	_ = x[Ident-48]
-	_ = x[LeftParen-49]
-	_ = x[RightParen-50]
-	_ = x[LeftBracket-51]
-	_ = x[RightBracket-52]
-	_ = x[LeftBrace-53]
-	_ = x[RightBrace-54]
+	_ = x[Enum-27]
+	_ = x[Record-28]
+	_ = x[Int-29]
+	_ = x[Float-30]
+	_ = x[String-31]
+	_ = x[Bool-32]
+	_ = x[Minus-33]
+	_ = x[Plus-34]
+	_ = x[Slash-35]
+	_ = x[Star-36]
+	_ = x[StarStar-37]
+	_ = x[Dot-38]
+	_ = x[DotDot-39]
+	_ = x[DotDotDot-40]
+	_ = x[Colon-41]
+	_ = x[Question-42]
+	_ = x[QuestionDot-43]
+	_ = x[QuestionQuestion-44]
+	_ = x[NewLine-45]
+	_ = x[Not-46]
+	_ = x[Caret-47]
+	_ = x[Arrow-48]
+	_ = x[Comma-49]
+	_ = x[Assign-50]
+	_ = x[PlusAssign-51]
+	_ = x[MinusAssign-52]
+	_ = x[StarAssign-53]
+	_ = x[SlashAssign-54]
+	_ = x[Equal-55]
+	_ = x[NotEqual-56]
+	_ = x[Less-57]
+	_ = x[LessEqual-58]
+	_ = x[Great-59]
+	_ = x[GreatEqual-60]
+	_ = x[Ident-61]
+	_ = x[Global-62]
+	_ = x[Symbol-63]
+	_ = x[LeftParen-64]
+	_ = x[RightParen-65]
+	_ = x[LeftBracket-66]
+	_ = x[RightBracket-67]
+	_ = x[LeftBrace-68]
+	_ = x[RightBrace-69]
 }
 
-const _Type_name = "IllegalEOFifisforswitchcasedefaultinstopnextwhileelsefunnonecatchblockobjectreturnsuperorandthisusevarconstIntFloatStringBool-+/*.:\\n!->Comma===!=<<=>>=Ident()[]{}"
+const _Type_name = "IllegalEOFifisforswitchcasedefaultinstopnextwhileelsefunnonecatchblockobjectreturnsuperorandthisusevarconstenumrecordIntFloatStringBool-+/***......:??.??\\n!^->Comma=+=-=*=/===!=<<=>>=IdentGlobalSymbol()[]{}"
 
-var _Type_index = [...]uint8{0, 7, 10, 12, 14, 17, 23, 27, 34, 36, 40, 44, 49, 53, 56, 60, 65, 70, 76, 82, 87, 89, 92, 96, 99, 102, 107, 110, 115, 121, 125, 126, 127, 128, 129, 130, 131, 133, 134, 136, 141, 142, 144, 146, 147, 149, 150, 152, 157, 158, 159, 160, 161, 162, 163}
+var _Type_index = [...]uint8{0, 7, 10, 12, 14, 17, 23, 27, 34, 36, 40, 44, 49, 53, 56, 60, 65, 70, 76, 82, 87, 89, 92, 96, 99, 102, 107, 111, 117, 120, 125, 131, 135, 136, 137, 138, 139, 141, 142, 144, 147, 148, 149, 151, 153, 155, 156, 157, 159, 164, 165, 167, 169, 171, 173, 175, 177, 178, 180, 181, 183, 188, 194, 200, 201, 202, 203, 204, 205, 206}
 
 func (i Type) String() string {
 	i -= 1